@@ -0,0 +1,208 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  审计日志
+-------------------------------------------------
+*/
+
+package auto_route
+
+import (
+    "io"
+    "log"
+    "net/http"
+    "os"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/kataras/iris/v12"
+
+    "github.com/zlyuancn/ziris"
+    "github.com/zlyuancn/ziris/ctx_info"
+)
+
+// 默认需要审计的请求方法
+var DefaultAuditMethods = []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// 审计记录
+type AuditRecord struct {
+    // 请求开始时间
+    Time time.Time `json:"time"`
+    // 请求id
+    RequestID string `json:"request_id,omitempty"`
+    // 调用者, 见 SetPrincipal, 没有认证中间件设置调用者时为空
+    Principal string `json:"principal"`
+    // 客户端ip
+    IP string `json:"ip"`
+    // 控制器名
+    Controller string `json:"controller"`
+    // 控制器方法
+    ControlMethod string `json:"control_method"`
+    // 请求方法
+    Method string `json:"method"`
+    // 控制器路由, 如 /api/user/login
+    Route string `json:"route"`
+    // 结尾路径参数
+    Params string `json:"params,omitempty"`
+    // 经过脱敏的请求路径和get参数
+    Path string `json:"path"`
+    // 经过脱敏和截断的请求体
+    Body string `json:"body,omitempty"`
+    // http状态码, 控制器方法发生panic时为500
+    Status int `json:"status"`
+    // 控制器方法是否发生了panic
+    Panic bool `json:"panic,omitempty"`
+    // 处理时间(毫秒)
+    LatencyMs float64 `json:"latency_ms"`
+}
+
+// 审计记录输出
+type AuditSink interface {
+    Write(record *AuditRecord) error
+}
+
+// 审计记录输出函数
+type AuditSinkFunc func(record *AuditRecord) error
+
+func (fn AuditSinkFunc) Write(record *AuditRecord) error {
+    return fn(record)
+}
+
+// 写入器输出
+type writerAuditSink struct {
+    w  io.Writer
+    mx sync.Mutex
+}
+
+// 创建将审计记录以一行json的形式写入w的输出, 如果需要滚动文件可以传入 ctx_info.NewRotateFile 创建的文件
+func NewWriterAuditSink(w io.Writer) AuditSink {
+    return &writerAuditSink{w: w}
+}
+
+func (m *writerAuditSink) Write(record *AuditRecord) error {
+    bs, err := json.Marshal(record)
+    if err != nil {
+        return err
+    }
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    _, err = m.w.Write(append(bs, '\n'))
+    return err
+}
+
+// 创建将审计记录以一行json的形式追加到文件的输出
+func NewFileAuditSink(name string) (AuditSink, error) {
+    f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+    if err != nil {
+        return nil, err
+    }
+    return NewWriterAuditSink(f), nil
+}
+
+// 创建将审计记录发送到ch的输出, ch已满时会阻塞直到可以发送
+func NewChanAuditSink(ch chan<- *AuditRecord) AuditSink {
+    return AuditSinkFunc(func(record *AuditRecord) error {
+        ch <- record
+        return nil
+    })
+}
+
+// 设置审计主体, 和 SetPrincipal 相同, 必须在认证中间件验证身份之后调用
+func SetAuditPrincipal(ctx iris.Context, principal string) {
+    SetPrincipal(ctx, principal)
+}
+
+// 获取审计主体, 和 GetPrincipal 相同, 没有认证中间件设置调用者时返回空字符串
+// 不会使用 basic auth 等未经验证的用户名, 避免客户端在审计记录中冒充其它调用者
+func GetAuditPrincipal(ctx iris.Context) string {
+    return GetPrincipal(ctx)
+}
+
+// 审计配置
+type AuditConfig struct {
+    // 审计记录输出, 不能为nil
+    Sink AuditSink
+    // 需要审计的请求方法, 默认为 DefaultAuditMethods
+    Methods []string
+    // 需要审计的控制器方法(如 login, 不区分大小写), 为空时审计所有控制器方法
+    ControlMethods []string
+    // 获取调用者, 默认为 GetAuditPrincipal
+    Principal func(ctx iris.Context) string
+    // 不记录请求体
+    WithoutBody bool
+    // 输出失败时调用, 默认使用标准库log输出错误
+    OnError func(err error, record *AuditRecord)
+}
+
+// 审计请求中间件, 在控制器方法调用完毕后(包括panic和被其它中间件停止)将审计记录写入输出
+// 每个控制器注册时可以传入不同的配置, 如
+//   auto_route.RegistryController(party, (*UserController)(nil), auto_route.Audit(auto_route.AuditConfig{Sink: sink}))
+func Audit(conf AuditConfig) ReqMiddleware {
+    if conf.Sink == nil {
+        panic("审计记录输出不能为nil")
+    }
+    methods := DefaultAuditMethods
+    if len(conf.Methods) > 0 {
+        methods = make([]string, len(conf.Methods))
+        for i, s := range conf.Methods {
+            methods[i] = strings.ToUpper(s)
+        }
+    }
+    controlMethods := make([]string, len(conf.ControlMethods))
+    for i, s := range conf.ControlMethods {
+        controlMethods[i] = strings.ToLower(s)
+    }
+    principal := conf.Principal
+    if principal == nil {
+        principal = GetAuditPrincipal
+    }
+    onError := conf.OnError
+    if onError == nil {
+        onError = func(err error, record *AuditRecord) {
+            log.Printf("写入审计记录失败: %s, route: %s, principal: %s", err, record.Route, record.Principal)
+        }
+    }
+
+    return func(ctx iris.Context, arg *ReqArg) {
+        if !containsString(methods, ctx.Method()) {
+            return
+        }
+        if len(controlMethods) > 0 && !containsString(controlMethods, strings.ToLower(arg.ControlMethod())) {
+            return
+        }
+
+        start := time.Now()
+        if !conf.WithoutBody {
+            ctx_info.CaptureBody(ctx)
+        }
+
+        arg.OnFinish(func(ctx iris.Context, arg *ReqArg) {
+            record := &AuditRecord{
+                Time:          start,
+                RequestID:     ctx_info.GetRequestID(ctx),
+                Principal:     principal(ctx),
+                IP:            ziris.ClientIP(ctx),
+                Controller:    arg.ControllerName(),
+                ControlMethod: arg.ControlMethod(),
+                Method:        ctx.Method(),
+                Route:         arg.Route(),
+                Params:        arg.Params(),
+                Path:          ctx_info.RedactURI(ctx.Request().URL),
+                Status:        ctx.GetStatusCode(),
+                Panic:         arg.IsPanic(),
+                LatencyMs:     float64(time.Since(start)) / float64(time.Millisecond),
+            }
+            if record.Panic {
+                record.Status = http.StatusInternalServerError
+            }
+            if !conf.WithoutBody {
+                record.Body = ctx_info.GetBody(ctx)
+            }
+            if err := conf.Sink.Write(record); err != nil {
+                onError(err, record)
+            }
+        })
+    }
+}
@@ -0,0 +1,76 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package auto_route
+
+import (
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/kataras/iris/v12"
+    "github.com/kataras/iris/v12/middleware/recover"
+)
+
+type TestAuditController struct{}
+
+func (t *TestAuditController) PostLogin(ctx iris.Context) {
+    var body map[string]interface{}
+    _ = ctx.ReadJSON(&body)
+    ctx.StatusCode(201)
+}
+
+func (t *TestAuditController) GetInfo(ctx iris.Context) {}
+
+func (t *TestAuditController) DeleteUser(ctx iris.Context) {
+    panic("delete")
+}
+
+func TestAudit(t *testing.T) {
+    ch := make(chan *AuditRecord, 10)
+    app := iris.New()
+    app.Use(recover.New())
+    app.Use(func(ctx iris.Context) {
+        SetAuditPrincipal(ctx, "admin")
+        ctx.Next()
+    })
+    RegistryController(app, (*TestAuditController)(nil), Audit(AuditConfig{Sink: NewChanAuditSink(ch)}))
+
+    req := httptest.NewRequest("POST", "/test_audit/login/1?a=b", strings.NewReader(`{"name":"a"}`))
+    req.Header.Set("Content-Type", "application/json")
+    testServe(t, app, req)
+    testServe(t, app, httptest.NewRequest("GET", "/test_audit/info", nil))
+    testServe(t, app, httptest.NewRequest("DELETE", "/test_audit/user", nil))
+
+    if len(ch) != 2 {
+        t.Fatal("审计记录数量错误", len(ch))
+    }
+    r := <-ch
+    if r.Principal != "admin" || r.Controller != "test_audit" || r.ControlMethod != "login" || r.Method != "POST" ||
+        r.Params != "1" || r.Path != "/test_audit/login/1?a=b" || r.Body != `{"name":"a"}` || r.Status != 201 || r.Panic {
+        t.Fatal("审计记录错误", *r)
+    }
+    r = <-ch
+    if r.ControlMethod != "user" || r.Status != 500 || !r.Panic {
+        t.Fatal("审计记录错误", *r)
+    }
+}
+
+func TestAuditPrincipalUnauthenticated(t *testing.T) {
+    ch := make(chan *AuditRecord, 10)
+    app := iris.New()
+    RegistryController(app, (*TestAuditController)(nil), Audit(AuditConfig{Sink: NewChanAuditSink(ch)}))
+
+    // 没有认证中间件时不会使用未经验证的 basic auth 用户名
+    req := httptest.NewRequest("POST", "/test_audit/login", nil)
+    req.SetBasicAuth("admin", "wrong-password")
+    testServe(t, app, req)
+    if r := <-ch; r.Principal != "" {
+        t.Fatal("调用者应该为空", r.Principal)
+    }
+}
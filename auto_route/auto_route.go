@@ -15,6 +15,8 @@ import (
 
     jsoniter "github.com/json-iterator/go"
     "github.com/kataras/iris/v12"
+
+    "github.com/zlyuancn/ziris/ctx_info"
 )
 
 const (
@@ -24,6 +26,8 @@ const (
     DefaultRequestMethod = "Get"
     // 在上下文中保存结尾路径的字段名
     ParamsFieldName = "params"
+    // 在 ctx.Values() 中保存请求参数的字段名
+    ReqArgField = "auto_route_req_arg"
 )
 
 var requestMethods = [...]string{"Get", "Post", "Delete", "Put", "Patch", "Head"}
@@ -190,6 +194,22 @@ func (m *controller) parserMethod(method string) (reqMethod string, controlMetho
     return DefaultRequestMethod, SnakeString(method)
 }
 
+// 返回控制器方法支持的请求方法, 如 [GET POST]
+// 和处理请求时一样, 控制器方法不支持的请求方法会使用空方法处理, 所以会包含空方法支持的请求方法
+func (m *controller) AllowMethods(controlMethod string) []string {
+    var out []string
+    for _, s := range requestMethods {
+        _, ok := m.methods[m.makeMethodKey(s, controlMethod)]
+        if !ok {
+            _, ok = m.methods[m.makeMethodKey(s, "")]
+        }
+        if ok {
+            out = append(out, strings.ToUpper(s))
+        }
+    }
+    return out
+}
+
 // 根据请求方法和控制器方法构建methods的key
 func (m *controller) makeMethodKey(reqMethod, controlMethod string) string {
     return fmt.Sprintf("%s/%s", strings.ToLower(reqMethod), controlMethod)
@@ -215,9 +235,12 @@ func (m *controller) handler(ctx iris.Context) {
     }
 
     reqArg := &ReqArg{
+        ctx:           ctx,
+        controller:    m,
         controlMethod: controlMethod,
         params:        params,
     }
+    ctx.Values().Set(ReqArgField, reqArg)
 
     if m.factory != nil {
         ctx = m.factory(ctx)
@@ -226,6 +249,19 @@ func (m *controller) handler(ctx iris.Context) {
         }
     }
 
+    completed := false
+    defer func() {
+        reqArg.panic = !completed
+        reqArg.finish(ctx)
+    }()
+    m.dispatch(ctx, reqArg)
+    completed = true
+}
+
+// 执行中间件并调用控制器方法
+func (m *controller) dispatch(ctx iris.Context, reqArg *ReqArg) {
+    reqMethod := ctx.Method()
+
     // 中间件
     for _, handler := range m.reqHandlers {
         handler(ctx, reqArg)
@@ -234,6 +270,7 @@ func (m *controller) handler(ctx iris.Context) {
         }
     }
 
+    ctx_info.Mark(ctx, ctx_info.MarkMiddleware)
     ctx.Params().Save(ParamsFieldName, reqArg.Params(), true)
     control, ok := m.methods[m.makeMethodKey(reqMethod, reqArg.ControlMethod())]
     if !ok {
@@ -243,6 +280,7 @@ func (m *controller) handler(ctx iris.Context) {
     }
 
     control.Handler(m, ctx)
+    ctx_info.Mark(ctx, ctx_info.MarkHandler)
 }
 
 // 转为蛇形字符串
@@ -13,6 +13,7 @@ import (
     "fmt"
     "io/ioutil"
     "net/http"
+    "net/http/httptest"
     "testing"
     "time"
 
@@ -79,7 +80,7 @@ func testRegistryController(t *testing.T, controller interface{}, factory Custom
 
         resp, err := http.Get("http://127.0.0.1:8080/test/fn/getparam?a=123")
         if err != nil {
-            t.Fatal(err)
+            t.Error(err)
             return
         }
         bs, _ := ioutil.ReadAll(resp.Body)
@@ -88,12 +89,13 @@ func testRegistryController(t *testing.T, controller interface{}, factory Custom
         if string(bs) == "get" {
             t.Log("成功")
         } else {
-            t.Fatal("失败了", string(bs))
+            t.Error("失败了", string(bs))
+            return
         }
 
         resp, err = http.Post("http://127.0.0.1:8080/test/fn/postparam?a=123", "", nil)
         if err != nil {
-            t.Fatal(err)
+            t.Error(err)
             return
         }
         bs, _ = ioutil.ReadAll(resp.Body)
@@ -102,7 +104,8 @@ func testRegistryController(t *testing.T, controller interface{}, factory Custom
         if string(bs) == "post" {
             t.Log("成功")
         } else {
-            t.Fatal("失败了", string(bs))
+            t.Error("失败了", string(bs))
+            return
         }
     }()
 
@@ -124,3 +127,34 @@ func TestRegistryWithCustomController(t *testing.T) {
         }
     })
 }
+
+// 构建app并直接处理一个请求, 不需要启动服务
+func testServe(t *testing.T, app *iris.Application, req *http.Request) *httptest.ResponseRecorder {
+    if err := app.Build(); err != nil {
+        t.Fatal(err)
+    }
+    w := httptest.NewRecorder()
+    app.ServeHTTP(w, req)
+    return w
+}
+
+type TestClientIpController struct{}
+
+func (t *TestClientIpController) GetInfo(ctx iris.Context) {
+    _, _ = ctx.WriteString(GetReqArg(ctx).ClientIP())
+}
+
+func TestReqArgClientIP(t *testing.T) {
+    var middlewareIP string
+    app := iris.New()
+    RegistryController(app, (*TestClientIpController)(nil), func(ctx iris.Context, arg *ReqArg) {
+        middlewareIP = arg.ClientIP()
+    })
+
+    req := httptest.NewRequest(http.MethodGet, "/test_client_ip/info", nil)
+    req.RemoteAddr = "1.2.3.4:1000"
+    w := testServe(t, app, req)
+    if w.Body.String() != "1.2.3.4" || middlewareIP != "1.2.3.4" {
+        t.Fatal("客户端ip错误", w.Body.String(), middlewareIP)
+    }
+}
@@ -0,0 +1,337 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  响应缓存
+-------------------------------------------------
+*/
+
+package auto_route
+
+import (
+    "container/list"
+    "crypto/sha1"
+    "encoding/hex"
+    "net/http"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/kataras/iris/v12"
+)
+
+const (
+    // 默认缓存条目数
+    DefaultCacheSize = 1000
+    // 默认缓存时间
+    DefaultCacheTTL = time.Minute
+    // 标记是否命中缓存的响应头, 值为 HIT 或 MISS
+    CacheStatusHeader = "X-Cache"
+)
+
+// 缓存的响应
+type CacheEntry struct {
+    StatusCode int
+    Header     http.Header
+    Body       []byte
+    ETag       string
+    ExpireAt   time.Time
+}
+
+// 响应缓存存储器
+type CacheStore interface {
+    // 获取缓存, 过期的缓存不应该返回
+    Get(key string) (*CacheEntry, bool)
+    // 设置缓存
+    Set(key string, entry *CacheEntry)
+    // 删除缓存
+    Delete(key string)
+}
+
+type lruCacheItem struct {
+    key   string
+    entry *CacheEntry
+}
+
+// 内存lru缓存存储器
+type lruCacheStore struct {
+    size  int
+    mx    sync.Mutex
+    ll    *list.List
+    items map[string]*list.Element
+}
+
+// 创建内存lru缓存存储器, size为最大缓存条目数, 如果size<=0则使用 DefaultCacheSize
+func NewLRUCacheStore(size int) CacheStore {
+    if size <= 0 {
+        size = DefaultCacheSize
+    }
+    return &lruCacheStore{
+        size:  size,
+        ll:    list.New(),
+        items: make(map[string]*list.Element, size),
+    }
+}
+
+func (m *lruCacheStore) Get(key string) (*CacheEntry, bool) {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+
+    e, ok := m.items[key]
+    if !ok {
+        return nil, false
+    }
+    item := e.Value.(*lruCacheItem)
+    if time.Now().After(item.entry.ExpireAt) {
+        m.ll.Remove(e)
+        delete(m.items, key)
+        return nil, false
+    }
+    m.ll.MoveToFront(e)
+    return item.entry, true
+}
+
+func (m *lruCacheStore) Set(key string, entry *CacheEntry) {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+
+    if e, ok := m.items[key]; ok {
+        e.Value.(*lruCacheItem).entry = entry
+        m.ll.MoveToFront(e)
+        return
+    }
+
+    m.items[key] = m.ll.PushFront(&lruCacheItem{key: key, entry: entry})
+    for m.ll.Len() > m.size {
+        e := m.ll.Back()
+        m.ll.Remove(e)
+        delete(m.items, e.Value.(*lruCacheItem).key)
+    }
+}
+
+func (m *lruCacheStore) Delete(key string) {
+    m.mx.Lock()
+    if e, ok := m.items[key]; ok {
+        m.ll.Remove(e)
+        delete(m.items, key)
+    }
+    m.mx.Unlock()
+}
+
+// 响应缓存配置
+type CacheConfig struct {
+    // 存储器, 为nil时使用内存lru存储器
+    Store CacheStore
+    // 默认缓存时间, 为0时使用 DefaultCacheTTL
+    TTL time.Duration
+    // 需要缓存的控制器方法(蛇形)和它的缓存时间, 缓存时间为0时使用 TTL
+    // 为nil时缓存所有的Get方法
+    Methods map[string]time.Duration
+    // 参与构建缓存key的get参数
+    Query []string
+    // 参与构建缓存key的请求头, 响应的 Vary 中的请求头都在其中时才会缓存
+    Headers []string
+    // 是否缓存带有 Authorization 或 Cookie 的请求, 开启后调用者(见 GetPrincipal)会参与构建缓存key, 调用者为空时仍然不缓存
+    // 默认不缓存, 避免将一个用户的响应返回给其它用户
+    AllowCredentials bool
+}
+
+// 响应缓存中间件, 只缓存Get方法状态码为200的响应
+// 缓存key由控制器方法路由, 路径参数, 指定的get参数和指定的请求头构成
+// 响应会带上 ETag 和 Cache-Control, 请求的 If-None-Match 匹配时返回 304
+// 请求的 Cache-Control 为 no-cache 时不读取缓存, 为 no-store 时不使用缓存
+// 请求带有 Authorization 或 Cookie 时不使用缓存, 见 CacheConfig.AllowCredentials
+// 响应带有 Set-Cookie, 或者 Vary 中有不参与构建缓存key的请求头时不会缓存
+func ResponseCache(conf CacheConfig) ReqMiddleware {
+    store := conf.Store
+    if store == nil {
+        store = NewLRUCacheStore(0)
+    }
+    if conf.TTL <= 0 {
+        conf.TTL = DefaultCacheTTL
+    }
+
+    return func(ctx iris.Context, arg *ReqArg) {
+        if ctx.Method() != http.MethodGet {
+            return
+        }
+
+        ttl := conf.TTL
+        if conf.Methods != nil {
+            t, ok := conf.Methods[arg.ControlMethod()]
+            if !ok {
+                return
+            }
+            if t > 0 {
+                ttl = t
+            }
+        }
+
+        reqCacheControl := strings.ToLower(ctx.GetHeader("Cache-Control"))
+        if strings.Contains(reqCacheControl, "no-store") {
+            return
+        }
+
+        principal := ""
+        if ctx.GetHeader("Authorization") != "" || ctx.GetHeader("Cookie") != "" {
+            if !conf.AllowCredentials {
+                return
+            }
+            if principal = GetPrincipal(ctx); principal == "" {
+                return
+            }
+        }
+
+        key := makeCacheKey(ctx, arg, &conf, principal)
+        if !strings.Contains(reqCacheControl, "no-cache") {
+            if entry, ok := store.Get(key); ok {
+                writeCacheEntry(ctx, entry)
+                arg.Stop()
+                return
+            }
+        }
+
+        ctx.Record()
+        rec, ok := ctx.IsRecording()
+        if !ok {
+            return
+        }
+        arg.OnFinish(func(ctx iris.Context, arg *ReqArg) {
+            if arg.IsPanic() || ctx.GetStatusCode() != http.StatusOK {
+                return
+            }
+
+            header := rec.Header()
+            if cc := strings.ToLower(header.Get("Cache-Control")); strings.Contains(cc, "no-store") || strings.Contains(cc, "private") {
+                return
+            }
+            // 不能将其它客户端的cookie重放给当前客户端
+            if len(header["Set-Cookie"]) > 0 || !isVaryCovered(header["Vary"], conf.Headers) {
+                return
+            }
+
+            body := append(([]byte)(nil), rec.Body()...)
+            etag := header.Get("ETag")
+            if etag == "" {
+                etag = makeETag(body)
+                header.Set("ETag", etag)
+            }
+            header.Set("Cache-Control", "max-age="+strconv.Itoa(int(ttl/time.Second)))
+            header.Set(CacheStatusHeader, "MISS")
+
+            entry := &CacheEntry{
+                StatusCode: http.StatusOK,
+                Header:     make(http.Header, len(header)),
+                Body:       body,
+                ETag:       etag,
+                ExpireAt:   time.Now().Add(ttl),
+            }
+            for k, v := range header {
+                entry.Header[k] = append(([]string)(nil), v...)
+            }
+            store.Set(key, entry)
+
+            if matchETag(ctx.GetHeader("If-None-Match"), etag) {
+                rec.ResetBody()
+                ctx.StatusCode(http.StatusNotModified)
+            }
+        })
+    }
+}
+
+// 构建缓存key, principal不为空时会参与构建缓存key
+func makeCacheKey(ctx iris.Context, arg *ReqArg, conf *CacheConfig, principal string) string {
+    var sb strings.Builder
+    sb.WriteString(arg.Route())
+    sb.WriteString("/")
+    sb.WriteString(arg.Params())
+    if principal != "" {
+        sb.WriteString("\x00principal=")
+        sb.WriteString(principal)
+    }
+
+    if len(conf.Query) > 0 {
+        query := ctx.Request().URL.Query()
+        for _, q := range conf.Query {
+            sb.WriteString("\x00")
+            sb.WriteString(q)
+            sb.WriteString("=")
+            sb.WriteString(strings.Join(query[q], ","))
+        }
+    }
+    for _, h := range conf.Headers {
+        sb.WriteString("\x00")
+        sb.WriteString(h)
+        sb.WriteString(":")
+        sb.WriteString(ctx.GetHeader(h))
+    }
+    return sb.String()
+}
+
+// 检查 Vary 中的请求头是否都参与了构建缓存key
+func isVaryCovered(vary []string, headers []string) bool {
+    for _, v := range vary {
+        for _, h := range strings.Split(v, ",") {
+            h = strings.TrimSpace(h)
+            if h == "" {
+                continue
+            }
+            if h == "*" {
+                return false
+            }
+            covered := false
+            for _, k := range headers {
+                if strings.EqualFold(h, k) {
+                    covered = true
+                    break
+                }
+            }
+            if !covered {
+                return false
+            }
+        }
+    }
+    return true
+}
+
+// 输出缓存的响应
+func writeCacheEntry(ctx iris.Context, entry *CacheEntry) {
+    header := ctx.ResponseWriter().Header()
+    for k, v := range entry.Header {
+        header[k] = append(([]string)(nil), v...)
+    }
+    maxAge := int(time.Until(entry.ExpireAt) / time.Second)
+    if maxAge < 0 {
+        maxAge = 0
+    }
+    header.Set("Cache-Control", "max-age="+strconv.Itoa(maxAge))
+    header.Set(CacheStatusHeader, "HIT")
+
+    if matchETag(ctx.GetHeader("If-None-Match"), entry.ETag) {
+        ctx.StatusCode(http.StatusNotModified)
+        return
+    }
+    ctx.StatusCode(entry.StatusCode)
+    _, _ = ctx.Write(entry.Body)
+}
+
+// 根据响应体生成ETag
+func makeETag(body []byte) string {
+    sum := sha1.Sum(body)
+    return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+// 检查 If-None-Match 是否匹配ETag
+func matchETag(ifNoneMatch, etag string) bool {
+    if ifNoneMatch == "" || etag == "" {
+        return false
+    }
+    for _, s := range strings.Split(ifNoneMatch, ",") {
+        s = strings.TrimSpace(s)
+        if s == "*" || strings.TrimPrefix(s, "W/") == strings.TrimPrefix(etag, "W/") {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,174 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package auto_route
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "strconv"
+    "testing"
+    "time"
+
+    "github.com/kataras/iris/v12"
+)
+
+type TestCacheController struct{}
+
+var testCacheCount int
+
+func (t *TestCacheController) List(ctx iris.Context) {
+    testCacheCount++
+    _, _ = ctx.WriteString("list " + strconv.Itoa(testCacheCount) + " " + ctx.URLParam("page"))
+}
+
+func TestResponseCache(t *testing.T) {
+    testCacheCount = 0
+    app := iris.New()
+    RegistryController(app, (*TestCacheController)(nil), ResponseCache(CacheConfig{
+        Methods: map[string]time.Duration{"list": time.Minute},
+        Query:   []string{"page"},
+    }))
+
+    w := testServe(t, app, httptest.NewRequest(http.MethodGet, "/test_cache/list?page=1", nil))
+    if w.Body.String() != "list 1 1" || w.Header().Get(CacheStatusHeader) != "MISS" {
+        t.Fatal("首次请求结果错误", w.Body.String(), w.Header())
+    }
+    etag := w.Header().Get("ETag")
+    if etag == "" || w.Header().Get("Cache-Control") != "max-age=60" {
+        t.Fatal("缓存响应头错误", w.Header())
+    }
+
+    w = testServe(t, app, httptest.NewRequest(http.MethodGet, "/test_cache/list?page=1&other=1", nil))
+    if w.Body.String() != "list 1 1" || w.Header().Get(CacheStatusHeader) != "HIT" {
+        t.Fatal("缓存结果错误", w.Body.String(), w.Header())
+    }
+
+    w = testServe(t, app, httptest.NewRequest(http.MethodGet, "/test_cache/list?page=2", nil))
+    if w.Body.String() != "list 2 2" {
+        t.Fatal("不同get参数结果错误", w.Body.String())
+    }
+
+    req := httptest.NewRequest(http.MethodGet, "/test_cache/list?page=1", nil)
+    req.Header.Set("If-None-Match", etag)
+    if w = testServe(t, app, req); w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+        t.Fatal("If-None-Match 处理错误", w.Code, w.Body.String())
+    }
+
+    req = httptest.NewRequest(http.MethodGet, "/test_cache/list?page=1", nil)
+    req.Header.Set("Cache-Control", "no-cache")
+    if w = testServe(t, app, req); w.Body.String() != "list 3 1" {
+        t.Fatal("no-cache 处理错误", w.Body.String())
+    }
+}
+
+func (t *TestCacheController) Cookie(ctx iris.Context) {
+    testCacheCount++
+    ctx.SetCookieKV("session", strconv.Itoa(testCacheCount))
+    _, _ = ctx.WriteString("cookie")
+}
+
+func (t *TestCacheController) Vary(ctx iris.Context) {
+    testCacheCount++
+    ctx.Header("Vary", "Accept-Language, "+ctx.URLParam("vary"))
+    _, _ = ctx.WriteString(ctx.GetHeader("Accept-Language"))
+}
+
+func TestResponseCacheUncacheable(t *testing.T) {
+    app := iris.New()
+    RegistryController(app, (*TestCacheController)(nil), ResponseCache(CacheConfig{
+        Headers: []string{"accept-language"},
+    }))
+
+    for i := 0; i < 2; i++ {
+        w := testServe(t, app, httptest.NewRequest(http.MethodGet, "/test_cache/cookie", nil))
+        if w.Header().Get(CacheStatusHeader) == "HIT" {
+            t.Fatal("带有 Set-Cookie 的响应不应该被缓存")
+        }
+    }
+
+    newReq := func(target, lang string) *http.Request {
+        req := httptest.NewRequest(http.MethodGet, target, nil)
+        req.Header.Set("Accept-Language", lang)
+        return req
+    }
+    testServe(t, app, newReq("/test_cache/vary", "en"))
+    if w := testServe(t, app, newReq("/test_cache/vary", "en")); w.Header().Get(CacheStatusHeader) != "HIT" || w.Body.String() != "en" {
+        t.Fatal("Vary 中的请求头参与了构建缓存key时应该被缓存", w.Header(), w.Body.String())
+    }
+    if w := testServe(t, app, newReq("/test_cache/vary", "zh")); w.Header().Get(CacheStatusHeader) == "HIT" || w.Body.String() != "zh" {
+        t.Fatal("不同的 Accept-Language 不应该命中缓存", w.Body.String())
+    }
+    for i := 0; i < 2; i++ {
+        if w := testServe(t, app, newReq("/test_cache/vary?vary=Cookie", "fr")); w.Header().Get(CacheStatusHeader) == "HIT" {
+            t.Fatal("Vary 中有不参与构建缓存key的请求头时不应该被缓存")
+        }
+    }
+}
+
+func TestResponseCacheCredentials(t *testing.T) {
+    newReq := func(auth, user string) *http.Request {
+        req := httptest.NewRequest(http.MethodGet, "/test_cache/list?page=1", nil)
+        if auth != "" {
+            req.Header.Set("Authorization", auth)
+        }
+        if user != "" {
+            req.Header.Set("X-User", user)
+        }
+        return req
+    }
+
+    // 默认不缓存带有凭证的请求
+    app := iris.New()
+    RegistryController(app, (*TestCacheController)(nil), ResponseCache(CacheConfig{}))
+    for i := 0; i < 2; i++ {
+        if w := testServe(t, app, newReq("Bearer a", "")); w.Header().Get(CacheStatusHeader) != "" {
+            t.Fatal("带有凭证的请求不应该使用缓存", w.Header())
+        }
+    }
+    req := httptest.NewRequest(http.MethodGet, "/test_cache/list?page=1", nil)
+    req.Header.Set("Cookie", "session=1")
+    if w := testServe(t, app, req); w.Header().Get(CacheStatusHeader) != "" {
+        t.Fatal("带有cookie的请求不应该使用缓存", w.Header())
+    }
+
+    // 允许缓存时按调用者区分缓存
+    app = iris.New()
+    RegistryController(app, (*TestCacheController)(nil), testAuthMiddleware, ResponseCache(CacheConfig{AllowCredentials: true}))
+    alice := testServe(t, app, newReq("Bearer a", "alice")).Body.String()
+    if w := testServe(t, app, newReq("Bearer a", "alice")); w.Header().Get(CacheStatusHeader) != "HIT" || w.Body.String() != alice {
+        t.Fatal("相同调用者应该命中缓存", w.Header(), w.Body.String())
+    }
+    if w := testServe(t, app, newReq("Bearer b", "bob")); w.Header().Get(CacheStatusHeader) == "HIT" || w.Body.String() == alice {
+        t.Fatal("不同调用者不应该命中缓存", w.Body.String())
+    }
+    for i := 0; i < 2; i++ {
+        if w := testServe(t, app, newReq("Bearer c", "")); w.Header().Get(CacheStatusHeader) != "" {
+            t.Fatal("没有调用者时不应该使用缓存", w.Header())
+        }
+    }
+}
+
+func TestLRUCacheStore(t *testing.T) {
+    store := NewLRUCacheStore(2)
+    expire := time.Now().Add(time.Minute)
+    store.Set("a", &CacheEntry{ExpireAt: expire})
+    store.Set("b", &CacheEntry{ExpireAt: expire})
+    store.Get("a")
+    store.Set("c", &CacheEntry{ExpireAt: expire})
+    if _, ok := store.Get("b"); ok {
+        t.Fatal("b 应该被淘汰")
+    }
+    if _, ok := store.Get("a"); !ok {
+        t.Fatal("a 不应该被淘汰")
+    }
+    store.Set("d", &CacheEntry{ExpireAt: time.Now().Add(-time.Second)})
+    if _, ok := store.Get("d"); ok {
+        t.Fatal("d 已过期")
+    }
+}
@@ -0,0 +1,183 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  跨域资源共享
+-------------------------------------------------
+*/
+
+package auto_route
+
+import (
+    "net/http"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/kataras/iris/v12"
+)
+
+// 跨域配置
+type CorsConfig struct {
+    // 允许的来源, 为空或包含 * 时允许所有来源, 支持通配符如 https://*.example.com
+    // 允许携带凭证时必须明确设置允许的来源
+    AllowOrigins []string
+    // 允许的请求方法, 仅在作为iris中间件时使用, 为空时允许所有请求方法
+    // 作为 ReqMiddleware 时会使用控制器方法实际支持的请求方法
+    AllowMethods []string
+    // 允许的请求头, 为空时会原样回应预检请求中的 Access-Control-Request-Headers
+    AllowHeaders []string
+    // 允许浏览器读取的响应头
+    ExposeHeaders []string
+    // 是否允许携带凭证(cookie, Authorization等), 开启时 AllowOrigins 不能为空或包含 *
+    AllowCredentials bool
+    // 预检结果的缓存时间, 为0时不设置
+    MaxAge time.Duration
+}
+
+type Cors struct {
+    conf         CorsConfig
+    allowAll     bool
+    allowMethods []string
+}
+
+// 创建跨域处理器
+// 允许携带凭证时如果允许所有来源, 任何网站都可以带着用户的凭证访问接口, 所以会panic
+func NewCors(conf CorsConfig) *Cors {
+    m := &Cors{conf: conf}
+    if len(conf.AllowOrigins) == 0 {
+        m.allowAll = true
+    }
+    for _, o := range conf.AllowOrigins {
+        if o == "*" {
+            m.allowAll = true
+        }
+    }
+    if m.allowAll && conf.AllowCredentials {
+        panic("允许携带凭证时必须明确设置允许的来源")
+    }
+
+    if len(conf.AllowMethods) == 0 {
+        for _, s := range requestMethods {
+            m.allowMethods = append(m.allowMethods, strings.ToUpper(s))
+        }
+    } else {
+        for _, s := range conf.AllowMethods {
+            m.allowMethods = append(m.allowMethods, strings.ToUpper(s))
+        }
+    }
+    return m
+}
+
+// 作为iris中间件使用, 预检请求会直接回应
+func (m *Cors) Handler() iris.Handler {
+    return func(ctx iris.Context) {
+        if m.handle(ctx, m.allowMethods) {
+            return
+        }
+        ctx.Next()
+    }
+}
+
+// 作为控制器的请求中间件使用, 预检请求会根据控制器方法支持的请求方法回应
+func (m *Cors) ReqMiddleware() ReqMiddleware {
+    return func(ctx iris.Context, arg *ReqArg) {
+        if m.handle(ctx, arg.AllowMethods()) {
+            arg.Stop()
+        }
+    }
+}
+
+// 检查来源是否被允许
+func (m *Cors) isAllowOrigin(origin string) bool {
+    if m.allowAll {
+        return true
+    }
+    for _, o := range m.conf.AllowOrigins {
+        if k := strings.Index(o, "*"); k != -1 {
+            prefix, suffix := o[:k], o[k+1:]
+            if len(origin) >= len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+                return true
+            }
+            continue
+        }
+        if strings.EqualFold(o, origin) {
+            return true
+        }
+    }
+    return false
+}
+
+// 处理跨域请求, 如果是预检请求会直接回应并返回true
+func (m *Cors) handle(ctx iris.Context, methods []string) bool {
+    origin := ctx.GetHeader("Origin")
+    if origin == "" {
+        return false
+    }
+
+    header := ctx.ResponseWriter().Header()
+    header.Add("Vary", "Origin")
+
+    preflight := ctx.Method() == http.MethodOptions && ctx.GetHeader("Access-Control-Request-Method") != ""
+    if !m.isAllowOrigin(origin) {
+        if preflight {
+            ctx.StatusCode(http.StatusForbidden)
+            return true
+        }
+        return false
+    }
+
+    if m.allowAll {
+        header.Set("Access-Control-Allow-Origin", "*")
+    } else {
+        header.Set("Access-Control-Allow-Origin", origin)
+    }
+    if m.conf.AllowCredentials {
+        header.Set("Access-Control-Allow-Credentials", "true")
+    }
+
+    if !preflight {
+        if len(m.conf.ExposeHeaders) > 0 {
+            header.Set("Access-Control-Expose-Headers", strings.Join(m.conf.ExposeHeaders, ", "))
+        }
+        return false
+    }
+
+    header.Add("Vary", "Access-Control-Request-Method")
+    header.Add("Vary", "Access-Control-Request-Headers")
+
+    if len(methods) == 0 {
+        ctx.StatusCode(http.StatusNotFound)
+        return true
+    }
+
+    allow := strings.Join(methods, ", ")
+    reqMethod := strings.ToUpper(ctx.GetHeader("Access-Control-Request-Method"))
+    if !containsString(methods, reqMethod) {
+        header.Set("Allow", allow)
+        ctx.StatusCode(http.StatusMethodNotAllowed)
+        return true
+    }
+
+    header.Set("Access-Control-Allow-Methods", allow)
+    if len(m.conf.AllowHeaders) > 0 {
+        header.Set("Access-Control-Allow-Headers", strings.Join(m.conf.AllowHeaders, ", "))
+    } else if h := ctx.GetHeader("Access-Control-Request-Headers"); h != "" {
+        header.Set("Access-Control-Allow-Headers", h)
+    }
+    if m.conf.MaxAge > 0 {
+        header.Set("Access-Control-Max-Age", strconv.Itoa(int(m.conf.MaxAge/time.Second)))
+    }
+
+    ctx.StatusCode(http.StatusNoContent)
+    return true
+}
+
+func containsString(ss []string, s string) bool {
+    for _, v := range ss {
+        if v == s {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,150 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package auto_route
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+
+    "github.com/kataras/iris/v12"
+)
+
+func TestCorsPreflight(t *testing.T) {
+    app := iris.New()
+    cors := NewCors(CorsConfig{
+        AllowOrigins: []string{"https://*.example.com"},
+        MaxAge:       time.Hour,
+    })
+    RegistryControllerWithName(app, (*TestController)(nil), "test", cors.ReqMiddleware())
+
+    req := httptest.NewRequest(http.MethodOptions, "/test/fn", nil)
+    req.Header.Set("Origin", "https://a.example.com")
+    req.Header.Set("Access-Control-Request-Method", "POST")
+    w := testServe(t, app, req)
+    if w.Code != http.StatusNoContent {
+        t.Fatal("状态码错误", w.Code)
+    }
+    if v := w.Header().Get("Access-Control-Allow-Methods"); v != "GET, POST" {
+        t.Fatal("Access-Control-Allow-Methods 错误", v)
+    }
+    if v := w.Header().Get("Access-Control-Allow-Origin"); v != "https://a.example.com" {
+        t.Fatal("Access-Control-Allow-Origin 错误", v)
+    }
+    if v := w.Header().Get("Access-Control-Max-Age"); v != "3600" {
+        t.Fatal("Access-Control-Max-Age 错误", v)
+    }
+
+    req = httptest.NewRequest(http.MethodOptions, "/test/fn", nil)
+    req.Header.Set("Origin", "https://a.example.com")
+    req.Header.Set("Access-Control-Request-Method", "DELETE")
+    if w = testServe(t, app, req); w.Code != http.StatusMethodNotAllowed {
+        t.Fatal("状态码错误", w.Code)
+    }
+
+    req = httptest.NewRequest(http.MethodOptions, "/test/fn", nil)
+    req.Header.Set("Origin", "https://evil.com")
+    req.Header.Set("Access-Control-Request-Method", "GET")
+    if w = testServe(t, app, req); w.Code != http.StatusForbidden {
+        t.Fatal("状态码错误", w.Code)
+    }
+
+    req = httptest.NewRequest(http.MethodGet, "/test/fn", nil)
+    req.Header.Set("Origin", "https://a.example.com")
+    w = testServe(t, app, req)
+    if w.Body.String() != "get" || w.Header().Get("Access-Control-Allow-Origin") != "https://a.example.com" {
+        t.Fatal("简单请求处理错误", w.Body.String(), w.Header())
+    }
+}
+
+type TestCorsMixController struct{}
+
+func (t *TestCorsMixController) GetFoo(ctx iris.Context) {
+    _, _ = ctx.WriteString("get foo")
+}
+
+func (t *TestCorsMixController) Post(ctx iris.Context) {
+    _, _ = ctx.WriteString("post")
+}
+
+func TestCorsPreflightMixedController(t *testing.T) {
+    app := iris.New()
+    cors := NewCors(CorsConfig{AllowOrigins: []string{"https://a.example.com"}})
+    RegistryController(app, (*TestCorsMixController)(nil), cors.ReqMiddleware())
+
+    // 控制器方法不支持的请求方法会使用空方法处理
+    w := testServe(t, app, httptest.NewRequest(http.MethodPost, "/test_cors_mix/foo", nil))
+    if w.Code != http.StatusOK || w.Body.String() != "post" {
+        t.Fatal("请求处理错误", w.Code, w.Body.String())
+    }
+
+    tests := []struct {
+        path   string
+        method string
+        code   int
+        allow  string
+    }{
+        {"/test_cors_mix/foo", "POST", http.StatusNoContent, "GET, POST"},
+        {"/test_cors_mix/foo", "GET", http.StatusNoContent, "GET, POST"},
+        {"/test_cors_mix/bar", "POST", http.StatusNoContent, "POST"},
+        {"/test_cors_mix/bar", "GET", http.StatusMethodNotAllowed, "POST"},
+    }
+    for _, tt := range tests {
+        req := httptest.NewRequest(http.MethodOptions, tt.path, nil)
+        req.Header.Set("Origin", "https://a.example.com")
+        req.Header.Set("Access-Control-Request-Method", tt.method)
+        w := testServe(t, app, req)
+        if w.Code != tt.code {
+            t.Fatal(tt.path, tt.method, "状态码错误", w.Code)
+        }
+        allow := w.Header().Get("Access-Control-Allow-Methods")
+        if w.Code == http.StatusMethodNotAllowed {
+            allow = w.Header().Get("Allow")
+        }
+        if allow != tt.allow {
+            t.Fatal(tt.path, tt.method, "允许的请求方法错误", allow)
+        }
+    }
+}
+
+func TestCorsCredentials(t *testing.T) {
+    for _, origins := range [][]string{nil, {"*"}, {"https://a.example.com", "*"}} {
+        func() {
+            defer func() {
+                if recover() == nil {
+                    t.Fatal(origins, "允许携带凭证时允许所有来源应该panic")
+                }
+            }()
+            NewCors(CorsConfig{AllowOrigins: origins, AllowCredentials: true})
+        }()
+    }
+
+    app := iris.New()
+    cors := NewCors(CorsConfig{AllowOrigins: []string{"https://a.example.com"}, AllowCredentials: true})
+    RegistryControllerWithName(app, (*TestController)(nil), "test", cors.ReqMiddleware())
+    for origin, expect := range map[string]int{"https://a.example.com": http.StatusNoContent, "https://evil.com": http.StatusForbidden} {
+        req := httptest.NewRequest(http.MethodOptions, "/test/fn", nil)
+        req.Header.Set("Origin", origin)
+        req.Header.Set("Access-Control-Request-Method", "GET")
+        w := testServe(t, app, req)
+        if w.Code != expect {
+            t.Fatal(origin, "状态码错误", w.Code)
+        }
+        if expect == http.StatusForbidden {
+            if w.Header().Get("Access-Control-Allow-Origin") != "" || w.Header().Get("Access-Control-Allow-Credentials") != "" {
+                t.Fatal("不允许的来源不应该返回跨域响应头", w.Header())
+            }
+            continue
+        }
+        if w.Header().Get("Access-Control-Allow-Origin") != origin || w.Header().Get("Access-Control-Allow-Credentials") != "true" {
+            t.Fatal("跨域响应头错误", w.Header())
+        }
+    }
+}
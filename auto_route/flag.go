@@ -0,0 +1,37 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package auto_route
+
+import (
+    "github.com/kataras/iris/v12"
+
+    "github.com/zlyuancn/ziris/ctx_info"
+)
+
+const (
+    // 控制器名样式标记
+    ControllerFlag ctx_info.FormatFlag = "controller"
+    // 控制器方法样式标记
+    ControlMethodFlag ctx_info.FormatFlag = "control_method"
+)
+
+func init() {
+    ctx_info.RegisterFormatFlag(string(ControllerFlag), func(ctx iris.Context) string {
+        if arg := GetReqArg(ctx); arg != nil {
+            return arg.ControllerName()
+        }
+        return ""
+    })
+    ctx_info.RegisterFormatFlag(string(ControlMethodFlag), func(ctx iris.Context) string {
+        if arg := GetReqArg(ctx); arg != nil {
+            return arg.ControlMethod()
+        }
+        return ""
+    })
+}
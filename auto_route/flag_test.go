@@ -0,0 +1,50 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package auto_route
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/kataras/iris/v12"
+
+    "github.com/zlyuancn/ziris/ctx_info"
+)
+
+type TestFlagController struct{}
+
+func (t *TestFlagController) GetInfo(ctx iris.Context) {}
+
+func TestControllerFlag(t *testing.T) {
+    if !ctx_info.HasFormatFlag(string(ControllerFlag)) || !ctx_info.HasFormatFlag(string(ControlMethodFlag)) {
+        t.Fatal("没有注册控制器样式标记")
+    }
+
+    var got string
+    app := iris.New()
+    RegistryController(app, (*TestFlagController)(nil), func(ctx iris.Context, arg *ReqArg) {
+        ctx.Next()
+        got = ctx_info.GetInfoOfLayout(ctx, "%(controller)s.%(control_method)s")
+    })
+    testServe(t, app, httptest.NewRequest(http.MethodGet, "/test_flag/info", nil))
+    if got != "test_flag.info" {
+        t.Fatal("输出错误", got)
+    }
+
+    // 不是控制器处理的请求输出为空
+    app = iris.New()
+    app.Get("/", func(ctx iris.Context) {
+        got = ctx_info.GetInfoOfLayout(ctx, "[%(controller)s][%(control_method)s]")
+    })
+    testServe(t, app, httptest.NewRequest(http.MethodGet, "/", nil))
+    if got != "[][]" {
+        t.Fatal("输出错误", got)
+    }
+}
@@ -0,0 +1,319 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  幂等请求
+-------------------------------------------------
+*/
+
+package auto_route
+
+import (
+    "bytes"
+    "crypto/sha256"
+    "encoding/hex"
+    "errors"
+    "io"
+    "io/ioutil"
+    "net/http"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/kataras/iris/v12"
+)
+
+const (
+    // 默认幂等键请求头
+    IdempotencyKeyHeader = "Idempotency-Key"
+    // 重放响应时设置的响应头
+    IdempotencyReplayedHeader = "Idempotency-Replayed"
+    // 默认幂等响应保存时间
+    DefaultIdempotencyTTL = 24 * time.Hour
+    // 默认计算摘要的请求体最大字节数
+    DefaultIdempotencyMaxBodySize = 1 << 20
+)
+
+// 相同幂等键的请求正在处理中
+var ErrIdempotencyInFlight = errors.New("相同幂等键的请求正在处理中")
+
+// 相同幂等键的请求内容不同
+var ErrIdempotencyMismatch = errors.New("幂等键已被内容不同的请求使用")
+
+// 带有幂等键的请求没有调用者
+var ErrIdempotencyNoPrincipal = errors.New("幂等请求需要验证调用者身份")
+
+// 带有幂等键的请求体太大
+var ErrIdempotencyBodyTooLarge = errors.New("幂等请求的请求体太大")
+
+// 保存的响应
+type IdempotencyResponse struct {
+    StatusCode int
+    Header     http.Header
+    Body       []byte
+    // 请求体的摘要, 用于检查重用幂等键的请求内容是否相同
+    Fingerprint string
+}
+
+// 幂等响应存储器
+type IdempotencyStore interface {
+    // 锁定key, 如果key已保存了响应则返回该响应, 如果key正在处理中则返回 ErrIdempotencyInFlight
+    Lock(key string) (*IdempotencyResponse, error)
+    // 保存响应并解锁key
+    Save(key string, resp *IdempotencyResponse) error
+    // 解锁key且不保存响应, 之后相同key的请求会重新处理
+    Unlock(key string)
+}
+
+type memoryIdempotencyEntry struct {
+    resp     *IdempotencyResponse
+    expireAt time.Time
+}
+
+// 内存幂等响应存储器
+type memoryIdempotencyStore struct {
+    ttl     time.Duration
+    mx      sync.Mutex
+    entries map[string]*memoryIdempotencyEntry
+    // 上次清理过期响应的时间
+    lastCleanup time.Time
+}
+
+// 内存幂等响应存储器清理过期响应的最大间隔
+const memoryIdempotencyCleanupInterval = time.Minute
+
+// 创建内存幂等响应存储器, ttl为响应保存时间, 如果ttl<=0则使用 DefaultIdempotencyTTL
+func NewMemoryIdempotencyStore(ttl time.Duration) IdempotencyStore {
+    if ttl <= 0 {
+        ttl = DefaultIdempotencyTTL
+    }
+    return &memoryIdempotencyStore{
+        ttl:     ttl,
+        entries: make(map[string]*memoryIdempotencyEntry),
+    }
+}
+
+func (m *memoryIdempotencyStore) Lock(key string) (*IdempotencyResponse, error) {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+
+    now := time.Now()
+    m.cleanup(now)
+
+    if e, ok := m.entries[key]; ok {
+        if e.resp == nil {
+            return nil, ErrIdempotencyInFlight
+        }
+        if now.Before(e.expireAt) {
+            return e.resp, nil
+        }
+    }
+    m.entries[key] = &memoryIdempotencyEntry{}
+    return nil, nil
+}
+
+// 清理过期的响应, 每个间隔最多清理一次, 避免每次加锁都遍历所有响应
+func (m *memoryIdempotencyStore) cleanup(now time.Time) {
+    interval := m.ttl
+    if interval > memoryIdempotencyCleanupInterval {
+        interval = memoryIdempotencyCleanupInterval
+    }
+    if now.Sub(m.lastCleanup) < interval {
+        return
+    }
+    m.lastCleanup = now
+    for k, e := range m.entries {
+        if e.resp != nil && now.After(e.expireAt) {
+            delete(m.entries, k)
+        }
+    }
+}
+
+func (m *memoryIdempotencyStore) Save(key string, resp *IdempotencyResponse) error {
+    m.mx.Lock()
+    m.entries[key] = &memoryIdempotencyEntry{resp: resp, expireAt: time.Now().Add(m.ttl)}
+    m.mx.Unlock()
+    return nil
+}
+
+func (m *memoryIdempotencyStore) Unlock(key string) {
+    m.mx.Lock()
+    if e, ok := m.entries[key]; ok && e.resp == nil {
+        delete(m.entries, key)
+    }
+    m.mx.Unlock()
+}
+
+// 幂等配置
+type IdempotencyConfig struct {
+    // 存储器, 为nil时使用内存存储器
+    Store IdempotencyStore
+    // 幂等键请求头, 默认为 IdempotencyKeyHeader
+    Header string
+    // 需要处理的请求方法, 默认为 POST
+    Methods []string
+    // 获取调用者, 幂等键只在同一个调用者的请求之间生效, 默认为 GetPrincipal
+    // 调用者为空的请求带有幂等键时会返回 401, 避免匿名调用者之间共用幂等键
+    Principal func(ctx iris.Context) string
+    // 计算摘要的请求体最大字节数, 超出时返回 413, 为0时使用 DefaultIdempotencyMaxBodySize
+    MaxBodySize int64
+}
+
+// 幂等请求中间件
+// 请求带有幂等键时, 首次请求的响应(状态码, 响应头, 响应体)会被保存, 相同幂等键的请求会直接重放保存的响应
+// 幂等键只在同一个调用者(见 IdempotencyConfig.Principal)的请求之间生效, 应该在认证中间件之后使用
+// 如果相同幂等键的请求正在处理中, 返回 409, 如果相同幂等键的请求体和保存时不同, 返回 422
+// 状态码 >= 500 或者控制器方法发生panic时不会保存响应, 客户端可以重试
+func Idempotency(conf IdempotencyConfig) ReqMiddleware {
+    store := conf.Store
+    if store == nil {
+        store = NewMemoryIdempotencyStore(0)
+    }
+    header := conf.Header
+    if header == "" {
+        header = IdempotencyKeyHeader
+    }
+    principal := conf.Principal
+    if principal == nil {
+        principal = GetPrincipal
+    }
+    maxBodySize := conf.MaxBodySize
+    if maxBodySize <= 0 {
+        maxBodySize = DefaultIdempotencyMaxBodySize
+    }
+    methods := []string{http.MethodPost}
+    if len(conf.Methods) > 0 {
+        methods = methods[:0]
+        for _, s := range conf.Methods {
+            methods = append(methods, strings.ToUpper(s))
+        }
+    }
+
+    return func(ctx iris.Context, arg *ReqArg) {
+        if !containsString(methods, ctx.Method()) {
+            return
+        }
+        idempotencyKey := ctx.GetHeader(header)
+        if idempotencyKey == "" {
+            return
+        }
+
+        caller := principal(ctx)
+        if caller == "" {
+            ctx.StatusCode(http.StatusUnauthorized)
+            _, _ = ctx.WriteString(ErrIdempotencyNoPrincipal.Error())
+            arg.Stop()
+            return
+        }
+
+        fingerprint, err := bodyFingerprint(ctx, maxBodySize)
+        if err != nil {
+            status := http.StatusBadRequest
+            if err == ErrIdempotencyBodyTooLarge {
+                status = http.StatusRequestEntityTooLarge
+            }
+            ctx.StatusCode(status)
+            _, _ = ctx.WriteString(err.Error())
+            arg.Stop()
+            return
+        }
+
+        key := makeIdempotencyKey(ctx.Method(), arg.Route()+"/"+arg.Params(), caller, idempotencyKey)
+        resp, err := store.Lock(key)
+        if err == ErrIdempotencyInFlight {
+            ctx.StatusCode(http.StatusConflict)
+            _, _ = ctx.WriteString(err.Error())
+            arg.Stop()
+            return
+        }
+        if err != nil {
+            ctx.StatusCode(http.StatusInternalServerError)
+            _, _ = ctx.WriteString(err.Error())
+            arg.Stop()
+            return
+        }
+
+        if resp != nil && resp.Fingerprint != fingerprint {
+            ctx.StatusCode(http.StatusUnprocessableEntity)
+            _, _ = ctx.WriteString(ErrIdempotencyMismatch.Error())
+            arg.Stop()
+            return
+        }
+
+        // 重放响应
+        if resp != nil {
+            h := ctx.ResponseWriter().Header()
+            for k, v := range resp.Header {
+                h[k] = append(([]string)(nil), v...)
+            }
+            h.Set(IdempotencyReplayedHeader, "true")
+            ctx.StatusCode(resp.StatusCode)
+            _, _ = ctx.Write(resp.Body)
+            arg.Stop()
+            return
+        }
+
+        ctx.Record()
+        rec, ok := ctx.IsRecording()
+        if !ok {
+            store.Unlock(key)
+            return
+        }
+        arg.OnFinish(func(ctx iris.Context, arg *ReqArg) {
+            status := ctx.GetStatusCode()
+            if arg.IsPanic() || status >= 500 {
+                store.Unlock(key)
+                return
+            }
+
+            resp := &IdempotencyResponse{
+                StatusCode:  status,
+                Header:      make(http.Header, len(rec.Header())),
+                Body:        append(([]byte)(nil), rec.Body()...),
+                Fingerprint: fingerprint,
+            }
+            for k, v := range rec.Header() {
+                resp.Header[k] = append(([]string)(nil), v...)
+            }
+            if err := store.Save(key, resp); err != nil {
+                store.Unlock(key)
+            }
+        })
+    }
+}
+
+// 使用请求方法, 路由, 调用者和幂等键生成存储的key
+// 每个部分带有长度前缀后计算摘要, 不同的组合不会得到相同的key
+func makeIdempotencyKey(parts ...string) string {
+    h := sha256.New()
+    for _, p := range parts {
+        _, _ = io.WriteString(h, strconv.Itoa(len(p)))
+        _, _ = io.WriteString(h, ":")
+        _, _ = io.WriteString(h, p)
+    }
+    return hex.EncodeToString(h.Sum(nil))
+}
+
+// 读取请求体并计算摘要, 读取后请求体会被放回去, 请求体超过max字节时返回 ErrIdempotencyBodyTooLarge
+func bodyFingerprint(ctx iris.Context, max int64) (string, error) {
+    req := ctx.Request()
+    if req.Body == nil {
+        return "", nil
+    }
+    if req.ContentLength > max {
+        return "", ErrIdempotencyBodyTooLarge
+    }
+    body, err := ioutil.ReadAll(io.LimitReader(req.Body, max+1))
+    if err != nil {
+        return "", err
+    }
+    if int64(len(body)) > max {
+        return "", ErrIdempotencyBodyTooLarge
+    }
+    _ = req.Body.Close()
+    req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+    sum := sha256.Sum256(body)
+    return hex.EncodeToString(sum[:]), nil
+}
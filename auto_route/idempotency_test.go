@@ -0,0 +1,119 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package auto_route
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "strconv"
+    "strings"
+    "testing"
+
+    "github.com/kataras/iris/v12"
+)
+
+type TestIdempotencyController struct{}
+
+var testIdempotencyCount int
+
+func (t *TestIdempotencyController) PostPay(ctx iris.Context) {
+    testIdempotencyCount++
+    ctx.Header("X-Count", strconv.Itoa(testIdempotencyCount))
+    ctx.StatusCode(201)
+    _, _ = ctx.WriteString("paid " + strconv.Itoa(testIdempotencyCount))
+}
+
+// 测试使用的认证中间件, 使用 X-User 作为调用者
+func testAuthMiddleware(ctx iris.Context, arg *ReqArg) {
+    if user := ctx.GetHeader("X-User"); user != "" {
+        SetPrincipal(ctx, user)
+    }
+}
+
+func TestIdempotency(t *testing.T) {
+    testIdempotencyCount = 0
+    app := iris.New()
+    store := NewMemoryIdempotencyStore(0)
+    RegistryController(app, (*TestIdempotencyController)(nil), testAuthMiddleware, Idempotency(IdempotencyConfig{Store: store, MaxBodySize: 16}))
+
+    newUserReq := func(key, user, body string) *http.Request {
+        req := httptest.NewRequest(http.MethodPost, "/test_idempotency/pay", strings.NewReader(body))
+        req.Header.Set(IdempotencyKeyHeader, key)
+        if user != "" {
+            req.Header.Set("X-User", user)
+        }
+        return req
+    }
+    newReq := func(key string) *http.Request {
+        return newUserReq(key, "alice", "")
+    }
+
+    w := testServe(t, app, newReq("a"))
+    if w.Code != 201 || w.Body.String() != "paid 1" {
+        t.Fatal("首次请求结果错误", w.Code, w.Body.String())
+    }
+
+    w = testServe(t, app, newReq("a"))
+    if w.Code != 201 || w.Body.String() != "paid 1" || w.Header().Get("X-Count") != "1" || w.Header().Get(IdempotencyReplayedHeader) != "true" {
+        t.Fatal("重放结果错误", w.Code, w.Body.String(), w.Header())
+    }
+
+    w = testServe(t, app, newReq("b"))
+    if w.Body.String() != "paid 2" {
+        t.Fatal("不同幂等键结果错误", w.Body.String())
+    }
+
+    // 不同调用者使用相同的幂等键不会重放
+    w = testServe(t, app, newUserReq("a", "bob", ""))
+    if w.Body.String() != "paid 3" || w.Header().Get(IdempotencyReplayedHeader) != "" {
+        t.Fatal("不同调用者结果错误", w.Body.String())
+    }
+
+    // 相同幂等键但请求体不同
+    if w = testServe(t, app, newUserReq("d", "bob", `{"amount":1}`)); w.Body.String() != "paid 4" {
+        t.Fatal("首次请求结果错误", w.Body.String())
+    }
+    if w = testServe(t, app, newUserReq("d", "bob", `{"amount":2}`)); w.Code != http.StatusUnprocessableEntity {
+        t.Fatal("请求体不同时状态码错误", w.Code, w.Body.String())
+    }
+
+    // 匿名调用者不能使用幂等键, 没有幂等键时正常处理
+    if w = testServe(t, app, newUserReq("a", "", "")); w.Code != http.StatusUnauthorized {
+        t.Fatal("匿名调用者状态码错误", w.Code, w.Body.String())
+    }
+    req := httptest.NewRequest(http.MethodPost, "/test_idempotency/pay", nil)
+    if w = testServe(t, app, req); w.Body.String() != "paid 5" {
+        t.Fatal("没有幂等键时结果错误", w.Body.String())
+    }
+
+    // 请求体超过限制
+    if w = testServe(t, app, newUserReq("e", "bob", `{"amount":1000000}`)); w.Code != http.StatusRequestEntityTooLarge {
+        t.Fatal("请求体太大时状态码错误", w.Code, w.Body.String())
+    }
+
+    // 模拟正在处理中的请求
+    if _, err := store.Lock(makeIdempotencyKey("POST", "/test_idempotency/pay/", "alice", "c")); err != nil {
+        t.Fatal(err)
+    }
+    if w = testServe(t, app, newReq("c")); w.Code != http.StatusConflict {
+        t.Fatal("并发请求状态码错误", w.Code)
+    }
+    if testIdempotencyCount != 5 {
+        t.Fatal("处理次数错误", testIdempotencyCount)
+    }
+}
+
+func TestMakeIdempotencyKey(t *testing.T) {
+    if makeIdempotencyKey("a b", "c") == makeIdempotencyKey("a", "b c") {
+        t.Fatal("不同的组合不应该得到相同的key")
+    }
+    if makeIdempotencyKey("a", "bc") != makeIdempotencyKey("a", "bc") {
+        t.Fatal("相同的组合应该得到相同的key")
+    }
+}
@@ -10,6 +10,8 @@ package auto_route
 
 import (
     "github.com/kataras/iris/v12"
+
+    "github.com/zlyuancn/ziris"
 )
 
 // 请求中间件, 会在构建自定义上下文之前调用
@@ -17,12 +19,30 @@ type ReqMiddleware func(ctx iris.Context, arg *ReqArg)
 
 // 请求参数
 type ReqArg struct {
+    ctx iris.Context
+    // 所属控制器
+    controller *controller
     // 控制器方法
     controlMethod string
     // 请求参数
     params string
     // 是否停止
     stop bool
+    // 控制器方法是否发生了panic
+    panic bool
+    // 完成回调
+    finishHandlers []ReqFinishHandler
+    // 客户端ip, 在第一次获取时解析
+    clientIP string
+}
+
+// 请求完成回调, 在控制器方法调用完毕或者请求被中间件停止后调用
+type ReqFinishHandler func(ctx iris.Context, arg *ReqArg)
+
+// 获取当前请求的请求参数, 如果请求不是由控制器处理的则返回nil
+func GetReqArg(ctx iris.Context) *ReqArg {
+    arg, _ := ctx.Values().Get(ReqArgField).(*ReqArg)
+    return arg
 }
 
 // 停止请求
@@ -35,6 +55,23 @@ func (m *ReqArg) IsStop() bool {
     return m.stop
 }
 
+// 添加请求完成回调, 回调会按照添加顺序的倒序调用, 即使控制器方法发生了panic也会调用
+func (m *ReqArg) OnFinish(fn ReqFinishHandler) {
+    m.finishHandlers = append(m.finishHandlers, fn)
+}
+
+// 返回控制器方法是否发生了panic, 应该只在完成回调中使用
+func (m *ReqArg) IsPanic() bool {
+    return m.panic
+}
+
+// 调用完成回调
+func (m *ReqArg) finish(ctx iris.Context) {
+    for i := len(m.finishHandlers) - 1; i >= 0; i-- {
+        m.finishHandlers[i](ctx, m)
+    }
+}
+
 // 返回控制器方法
 func (m *ReqArg) ControlMethod() string {
     return m.controlMethod
@@ -45,6 +82,32 @@ func (m *ReqArg) Params() string {
     return m.params
 }
 
+// 返回客户端ip, 见 ziris.ClientIP, 可以用于限流和鉴权
+func (m *ReqArg) ClientIP() string {
+    if m.clientIP == "" {
+        m.clientIP = ziris.ClientIP(m.ctx)
+    }
+    return m.clientIP
+}
+
+// 返回控制器名
+func (m *ReqArg) ControllerName() string {
+    return m.controller.name
+}
+
+// 返回控制器方法的路由, 不包含路径参数, 如 /v1/user/login
+func (m *ReqArg) Route() string {
+    if m.controlMethod == "" {
+        return m.controller.parentPath + "/" + m.controller.name
+    }
+    return m.controller.parentPath + "/" + m.controller.name + "/" + m.controlMethod
+}
+
+// 返回当前控制器方法支持的请求方法, 如 [GET POST]
+func (m *ReqArg) AllowMethods() []string {
+    return m.controller.AllowMethods(m.controlMethod)
+}
+
 // 设置控制器方法, 注意, 它应该是蛇形的
 func (m *ReqArg) SetControlMethod(method string) {
     m.controlMethod = method
@@ -0,0 +1,27 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  调用者
+-------------------------------------------------
+*/
+
+package auto_route
+
+import (
+    "github.com/kataras/iris/v12"
+)
+
+// 调用者在 ctx.Values() 中的字段名
+const PrincipalField = "auto_route_principal"
+
+// 设置调用者, 如用户id, 应该在认证中间件验证身份之后调用
+// 幂等请求等中间件会使用它区分调用者, 不要使用未经验证的数据(如 basic auth 的用户名)
+func SetPrincipal(ctx iris.Context, principal string) {
+    ctx.Values().Set(PrincipalField, principal)
+}
+
+// 获取调用者, 没有使用 SetPrincipal 设置时返回空字符串
+func GetPrincipal(ctx iris.Context) string {
+    return ctx.Values().GetString(PrincipalField)
+}
@@ -0,0 +1,23 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package auto_route
+
+import (
+    "github.com/kataras/iris/v12"
+
+    "github.com/zlyuancn/ziris/ctx_info"
+)
+
+// 请求id中间件, 读取或生成请求id, 并回应到响应头中
+// 控制器方法中可以使用 ctx_info.GetRequestID(ctx) 获取请求id
+func RequestID() ReqMiddleware {
+    return func(ctx iris.Context, arg *ReqArg) {
+        ctx_info.EnsureRequestID(ctx)
+    }
+}
@@ -0,0 +1,42 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package auto_route
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/kataras/iris/v12"
+
+    "github.com/zlyuancn/ziris/ctx_info"
+)
+
+type TestRequestIdController struct{}
+
+func (t *TestRequestIdController) GetInfo(ctx iris.Context) {
+    _, _ = ctx.WriteString(ctx_info.GetRequestID(ctx))
+}
+
+func TestRequestID(t *testing.T) {
+    app := iris.New()
+    RegistryController(app, (*TestRequestIdController)(nil), RequestID())
+
+    req := httptest.NewRequest(http.MethodGet, "/test_request_id/info", nil)
+    req.Header.Set(ctx_info.RequestIDHeader, "abc")
+    w := testServe(t, app, req)
+    if w.Body.String() != "abc" || w.Header().Get(ctx_info.RequestIDHeader) != "abc" {
+        t.Fatal("请求id错误", w.Body.String(), w.Header())
+    }
+
+    w = testServe(t, app, httptest.NewRequest(http.MethodGet, "/test_request_id/info", nil))
+    if id := w.Body.String(); id == "" || w.Header().Get(ctx_info.RequestIDHeader) != id {
+        t.Fatal("生成的请求id错误", id, w.Header())
+    }
+}
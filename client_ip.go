@@ -0,0 +1,178 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  客户端ip
+-------------------------------------------------
+*/
+
+package ziris
+
+import (
+    "fmt"
+    "net"
+    "net/http"
+    "strings"
+
+    "github.com/kataras/iris/v12"
+)
+
+// 默认读取客户端ip的header, 排在前面的优先
+var DefaultClientIPHeaders = []string{"X-Forwarded-For", "X-Real-IP", "Forwarded"}
+
+// 客户端ip解析器
+type IPResolver struct {
+    trustedProxies []*net.IPNet
+    headers        []string
+}
+
+// 创建客户端ip解析器
+// trustedProxies为可信代理的ip或cidr, 如 10.0.0.0/8, 只有直接连接的ip是可信代理时才会读取header
+// headers为读取客户端ip的header, 排在前面的优先, 为空时使用 DefaultClientIPHeaders
+func NewIPResolver(trustedProxies []string, headers ...string) (*IPResolver, error) {
+    m := &IPResolver{headers: DefaultClientIPHeaders}
+    if len(headers) > 0 {
+        m.headers = append(([]string)(nil), headers...)
+    }
+    for _, s := range trustedProxies {
+        ipNet, err := parseCIDR(s)
+        if err != nil {
+            return nil, err
+        }
+        m.trustedProxies = append(m.trustedProxies, ipNet)
+    }
+    return m, nil
+}
+
+// 解析ip或cidr
+func parseCIDR(s string) (*net.IPNet, error) {
+    s = strings.TrimSpace(s)
+    if strings.Contains(s, "/") {
+        _, ipNet, err := net.ParseCIDR(s)
+        if err != nil {
+            return nil, fmt.Errorf("无效的cidr: %s", s)
+        }
+        return ipNet, nil
+    }
+    ip := net.ParseIP(s)
+    if ip == nil {
+        return nil, fmt.Errorf("无效的ip: %s", s)
+    }
+    if ip4 := ip.To4(); ip4 != nil {
+        return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+    }
+    return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+// 检查ip是否为可信代理
+func (m *IPResolver) isTrusted(ip net.IP) bool {
+    for _, ipNet := range m.trustedProxies {
+        if ipNet.Contains(ip) {
+            return true
+        }
+    }
+    return false
+}
+
+// 解析客户端ip
+// 如果直接连接的ip不是可信代理, 直接返回它
+// 否则按照header的优先级读取, 对于 X-Forwarded-For 和 Forwarded 会从右往左跳过可信代理, 返回第一个不可信的ip
+func (m *IPResolver) ClientIP(r *http.Request) string {
+    remote := remoteIP(r.RemoteAddr)
+    ip := net.ParseIP(remote)
+    if ip == nil || !m.isTrusted(ip) {
+        return remote
+    }
+
+    for _, h := range m.headers {
+        var ips []string
+        switch strings.ToLower(h) {
+        case "forwarded":
+            ips = parseForwarded(r.Header[http.CanonicalHeaderKey(h)])
+        default:
+            for _, v := range r.Header[http.CanonicalHeaderKey(h)] {
+                for _, s := range strings.Split(v, ",") {
+                    ips = append(ips, strings.TrimSpace(s))
+                }
+            }
+        }
+        if client := m.rightmostUntrusted(ips); client != "" {
+            return client
+        }
+    }
+    return remote
+}
+
+// 从右往左返回第一个不可信的ip, 如果都是可信代理则返回最左边的ip
+func (m *IPResolver) rightmostUntrusted(ips []string) string {
+    leftmost := ""
+    for i := len(ips) - 1; i >= 0; i-- {
+        ip := net.ParseIP(remoteIP(ips[i]))
+        if ip == nil {
+            continue
+        }
+        leftmost = ip.String()
+        if !m.isTrusted(ip) {
+            return leftmost
+        }
+    }
+    return leftmost
+}
+
+// 解析 Forwarded(RFC 7239) 中的 for 参数
+func parseForwarded(values []string) []string {
+    var out []string
+    for _, v := range values {
+        for _, elem := range strings.Split(v, ",") {
+            for _, pair := range strings.Split(elem, ";") {
+                pair = strings.TrimSpace(pair)
+                if len(pair) < 4 || !strings.EqualFold(pair[:4], "for=") {
+                    continue
+                }
+                out = append(out, strings.Trim(pair[4:], `"`))
+            }
+        }
+    }
+    return out
+}
+
+// 去掉地址中的端口和ipv6的方括号
+func remoteIP(addr string) string {
+    addr = strings.TrimSpace(addr)
+    if host, _, err := net.SplitHostPort(addr); err == nil {
+        return host
+    }
+    return strings.Trim(addr, "[]")
+}
+
+var defaultIPResolver, _ = NewIPResolver(nil)
+
+// 设置全局可信代理, 传入ip或cidr, 如 10.0.0.0/8
+func SetTrustedProxies(trustedProxies ...string) error {
+    resolver, err := NewIPResolver(trustedProxies, defaultIPResolver.headers...)
+    if err != nil {
+        return err
+    }
+    defaultIPResolver = resolver
+    return nil
+}
+
+// 设置全局读取客户端ip的header, 排在前面的优先
+func SetClientIPHeaders(headers ...string) {
+    resolver := *defaultIPResolver
+    resolver.headers = DefaultClientIPHeaders
+    if len(headers) > 0 {
+        resolver.headers = append(([]string)(nil), headers...)
+    }
+    defaultIPResolver = &resolver
+}
+
+// 使用全局可信代理设置获取客户端ip
+// 没有设置可信代理时使用 ctx.RemoteAddr(), 此时会按照 iris 的 RemoteAddrHeaders 配置读取header
+func ClientIP(ctx iris.Context) string {
+    resolver := defaultIPResolver
+    if len(resolver.trustedProxies) == 0 {
+        return ctx.RemoteAddr()
+    }
+    return resolver.ClientIP(ctx.Request())
+}
@@ -0,0 +1,93 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package ziris
+
+import (
+    "net/http/httptest"
+    "testing"
+
+    "github.com/kataras/iris/v12"
+)
+
+func TestIPResolver(t *testing.T) {
+    resolver, err := NewIPResolver([]string{"10.0.0.0/8", "192.168.1.1"})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    tests := []struct {
+        remote string
+        header map[string]string
+        expect string
+    }{
+        {"1.1.1.1:1000", map[string]string{"X-Forwarded-For": "2.2.2.2"}, "1.1.1.1"},
+        {"10.0.0.1:1000", map[string]string{"X-Forwarded-For": "3.3.3.3, 2.2.2.2, 10.0.0.2"}, "2.2.2.2"},
+        {"10.0.0.1:1000", map[string]string{"X-Real-IP": "2.2.2.2"}, "2.2.2.2"},
+        {"192.168.1.1:1000", map[string]string{"Forwarded": `for="[2001:db8::1]:4711";proto=https, for=10.0.0.3`}, "2001:db8::1"},
+        {"10.0.0.1:1000", map[string]string{"X-Forwarded-For": "10.0.0.5"}, "10.0.0.5"},
+        {"10.0.0.1:1000", nil, "10.0.0.1"},
+    }
+    for _, test := range tests {
+        req := httptest.NewRequest("GET", "/", nil)
+        req.RemoteAddr = test.remote
+        for k, v := range test.header {
+            req.Header.Set(k, v)
+        }
+        if ip := resolver.ClientIP(req); ip != test.expect {
+            t.Fatalf("%v 期望 %s, 实际 %s", test, test.expect, ip)
+        }
+    }
+
+    if _, err := NewIPResolver([]string{"x"}); err == nil {
+        t.Fatal("无效的cidr应该返回错误")
+    }
+}
+
+func TestClientIP(t *testing.T) {
+    defer SetTrustedProxies()
+
+    var ip string
+    app := iris.New()
+    app.Get("/", func(ctx iris.Context) {
+        ip = ClientIP(ctx)
+    })
+    if err := app.Build(); err != nil {
+        t.Fatal(err)
+    }
+    serve := func(header string) string {
+        req := httptest.NewRequest("GET", "/", nil)
+        req.RemoteAddr = "10.0.0.1:1000"
+        req.Header.Set("X-Real-Ip", header)
+        app.ServeHTTP(httptest.NewRecorder(), req)
+        return ip
+    }
+
+    // 没有设置可信代理时使用 iris 的 RemoteAddrHeaders 配置
+    if s := serve("2.2.2.2"); s != "10.0.0.1" {
+        t.Fatal("没有开启 RemoteAddrHeaders 时应该使用直接连接的ip", s)
+    }
+    app.Configure(iris.WithRemoteAddrHeader("X-Real-Ip"))
+    if s := serve("2.2.2.2"); s != "2.2.2.2" {
+        t.Fatal("应该读取 RemoteAddrHeaders 中的header", s)
+    }
+
+    // 设置可信代理后使用可信代理解析
+    if err := SetTrustedProxies("10.0.0.0/8"); err != nil {
+        t.Fatal(err)
+    }
+    if s := serve("3.3.3.3"); s != "3.3.3.3" {
+        t.Fatal("应该读取可信代理设置的header", s)
+    }
+    if err := SetTrustedProxies("192.168.0.0/16"); err != nil {
+        t.Fatal(err)
+    }
+    if s := serve("3.3.3.3"); s != "10.0.0.1" {
+        t.Fatal("直接连接的ip不是可信代理时不应该读取header", s)
+    }
+}
@@ -10,6 +10,7 @@ package ziris
 
 import (
     "fmt"
+    "os"
 )
 
 type ColorType uint8
@@ -25,7 +26,35 @@ const (
     ColorWhite                          // 白
 )
 
-// 构建彩色文本
+// 是否输出颜色, 设置了环境变量 NO_COLOR 或者标准输出不是终端时默认不输出颜色
+var colorEnabled = detectColorEnabled(os.Stdout)
+
+// 检查是否应该向文件输出颜色
+func detectColorEnabled(f *os.File) bool {
+    if os.Getenv("NO_COLOR") != "" {
+        return false
+    }
+    fi, err := f.Stat()
+    if err != nil {
+        return false
+    }
+    return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// 设置是否输出颜色
+func SetColorEnabled(enabled bool) {
+    colorEnabled = enabled
+}
+
+// 返回是否输出颜色
+func IsColorEnabled() bool {
+    return colorEnabled
+}
+
+// 构建彩色文本, 如果不输出颜色则直接返回文本
 func MakeColorText(color ColorType, a string) string {
+    if !colorEnabled {
+        return a
+    }
     return fmt.Sprintf("\x1b[%dm%s\x1b[0m", color, a)
 }
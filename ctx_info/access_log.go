@@ -0,0 +1,136 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  访问日志格式
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/kataras/iris/v12"
+)
+
+// Common Log Format, 如
+//   127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326
+const CommonLayout = `%(ip)s - %(remote_user)s [%(time)s] "%(method)s %(fullpath)s %(proto)s" %(status)s %(resp_size:clf)s`
+
+// Combined Log Format, 在 CommonLayout 后面增加了 Referer 和 User-Agent
+const CombinedLayout = CommonLayout + ` "%(referer)s" "%(user_agent)s"`
+
+// W3C Extended Log Format 的字段, 写日志文件时应该先写入这两行
+const W3CHeader = "#Version: 1.0\n#Fields: " + W3CFields
+const W3CFields = "date time c-ip cs-username cs-method cs-uri sc-status sc-bytes time-taken cs(User-Agent) cs(Referer)"
+
+// W3C Extended Log Format, 字段见 W3CFields, 时间为UTC, time-taken 的单位为毫秒
+const W3CLayout = `%(time:w3c)s %(ip)s %(remote_user:w3c)s %(method)s %(fullpath)s %(status)s %(resp_size)s %(latency_ms)s %(user_agent:w3c)s %(referer:w3c)s`
+
+// 时间标记的预定义格式
+var timeFormats = map[string]func(t time.Time) string{
+    "clf": func(t time.Time) string {
+        return t.Format("02/Jan/2006:15:04:05 -0700")
+    },
+    "w3c": func(t time.Time) string {
+        return t.UTC().Format("2006-01-02 15:04:05")
+    },
+    "rfc3339": func(t time.Time) string {
+        return t.Format(time.RFC3339)
+    },
+    "rfc3339nano": func(t time.Time) string {
+        return t.Format(time.RFC3339Nano)
+    },
+    "unix": func(t time.Time) string {
+        return strconv.FormatInt(t.Unix(), 10)
+    },
+    "unixms": func(t time.Time) string {
+        return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+    },
+}
+
+// 时间标记的默认格式
+var defaultTimeFormat = "clf"
+
+// 设置时间标记 %(time)s 的默认格式, 可以是预定义格式 clf, w3c, rfc3339, rfc3339nano, unix, unixms
+// 或者 time.Format 的格式, 如 2006-01-02 15:04:05
+func SetTimeFormat(format string) {
+    if format == "" {
+        format = "clf"
+    }
+    defaultTimeFormat = format
+}
+
+// 按照格式输出请求开始时间, 未设置开始时间时使用当前时间
+func formatTime(ctx iris.Context, format string) string {
+    t, ok := getStartTime(ctx)
+    if !ok {
+        t = time.Now()
+    }
+    if format == "" {
+        format = defaultTimeFormat
+    }
+    if fn, ok := timeFormats[format]; ok {
+        return fn(t)
+    }
+    return t.Format(format)
+}
+
+// 获取 basic auth 的用户名, 没有时返回 -
+// 如果mode为w3c, 空格会被替换为+, 否则会转义双引号和反斜杠
+func getRemoteUser(ctx iris.Context, mode string) string {
+    if user, _, ok := ctx.Request().BasicAuth(); ok && user != "" {
+        return escapeLogValue(user, mode)
+    }
+    return "-"
+}
+
+// 获取访问日志中的Referer, 没有时返回 -, 其中的get参数会按照 Redaction.QueryParams 脱敏
+func getReferer(ctx iris.Context, mode string) string {
+    v := getHeaderValue(ctx.Request().Header, "Referer")
+    if v == "" {
+        return "-"
+    }
+    if k := strings.IndexByte(v, '?'); k != -1 && len(redaction.queryParams) > 0 {
+        query, fragment := v[k+1:], ""
+        if i := strings.IndexByte(query, '#'); i != -1 {
+            query, fragment = query[:i], query[i:]
+        }
+        v = v[:k+1] + redactQueryString(query, redaction.queryParams) + fragment
+    }
+    return escapeLogValue(v, mode)
+}
+
+// 获取访问日志中的header值, 没有时返回 -
+// 如果mode为w3c, 空格会被替换为+, 否则会转义双引号和反斜杠, 可以直接放在双引号中
+func getLogHeader(ctx iris.Context, name, mode string) string {
+    v := getHeaderValue(ctx.Request().Header, name)
+    if v == "" {
+        return "-"
+    }
+    return escapeLogValue(v, mode)
+}
+
+// 转义访问日志中的值
+func escapeLogValue(s, mode string) string {
+    if mode == "w3c" {
+        return strings.Replace(s, " ", "+", -1)
+    }
+    var sb strings.Builder
+    for i := 0; i < len(s); i++ {
+        switch c := s[i]; {
+        case c == '"' || c == '\\':
+            sb.WriteByte('\\')
+            sb.WriteByte(c)
+        case c < 0x20 || c == 0x7f:
+            sb.WriteString(`\x`)
+            sb.WriteString(strconv.FormatUint(uint64(c)|0x100, 16)[1:])
+        default:
+            sb.WriteByte(c)
+        }
+    }
+    return sb.String()
+}
@@ -0,0 +1,91 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/kataras/iris/v12"
+)
+
+func TestAccessLogLayout(t *testing.T) {
+    common := MustCompileLayout(CommonLayout)
+    combined := MustCompileLayout(CombinedLayout)
+    w3c := MustCompileLayout(W3CLayout)
+
+    start := time.Date(2000, 10, 10, 13, 55, 36, 0, time.FixedZone("", -7*3600))
+    app := iris.New()
+    app.Use(func(ctx iris.Context) {
+        SetStartTimeOf(ctx, start)
+        ctx.Next()
+
+        clf := `192.0.2.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /a.gif?b=1 HTTP/1.1" 200 2`
+        if s := common.Render(ctx); s != clf {
+            t.Fatal("输出错误", s)
+        }
+        if s := combined.Render(ctx); s != clf+` "-" "Mozilla/5.0 \"x\""` {
+            t.Fatal("输出错误", s)
+        }
+        if s := w3c.Render(ctx); !strings.HasPrefix(s, `2000-10-10 20:55:36 192.0.2.1 frank GET /a.gif?b=1 200 2 `) || !strings.HasSuffix(s, ` Mozilla/5.0+"x" -`) {
+            t.Fatal("输出错误", s)
+        }
+    })
+    app.Get("/{any:path}", func(ctx iris.Context) {
+        _, _ = ctx.WriteString("ok")
+    })
+    if err := app.Build(); err != nil {
+        t.Fatal(err)
+    }
+    req := httptest.NewRequest("GET", "/a.gif?b=1", nil)
+    req.SetBasicAuth("frank", "secret")
+    req.Header.Set("User-Agent", `Mozilla/5.0 "x"`)
+    app.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestAccessLogFieldEscape(t *testing.T) {
+    defer SetRedaction(DefaultRedaction())
+    SetRedaction(&Redaction{QueryParams: []string{"token"}})
+
+    w3c := MustCompileLayout(W3CLayout)
+    app := iris.New()
+    app.Use(func(ctx iris.Context) {
+        SetStartTime(ctx)
+        ctx.Next()
+
+        tests := []struct {
+            layout string
+            expect string
+        }{
+            {"%(remote_user)s", `john \"j\" doe`},
+            {"%(remote_user:w3c)s", `john+"j"+doe`},
+            {"%(referer)s", `https://a.com/x y?token=******&b=1#top`},
+            {"%(referer:w3c)s", `https://a.com/x+y?token=******&b=1#top`},
+        }
+        for _, tt := range tests {
+            if s := GetInfoOfLayout(ctx, tt.layout); s != tt.expect {
+                t.Fatal(tt.layout, "输出错误", s)
+            }
+        }
+        // W3C的字段以空格分隔, 每个字段都不能包含空格
+        if fields := strings.Split(w3c.Render(ctx), " "); len(fields) != 11 {
+            t.Fatal("W3C字段数量错误", fields)
+        }
+    })
+    app.Get("/", func(ctx iris.Context) {})
+    if err := app.Build(); err != nil {
+        t.Fatal(err)
+    }
+    req := httptest.NewRequest("GET", "/", nil)
+    req.SetBasicAuth(`john "j" doe`, "secret")
+    req.Header.Set("Referer", "https://a.com/x y?token=abc&b=1#top")
+    app.ServeHTTP(httptest.NewRecorder(), req)
+}
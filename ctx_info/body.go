@@ -0,0 +1,141 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  请求体
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "bytes"
+    "io"
+    "strconv"
+
+    "github.com/kataras/iris/v12"
+)
+
+// 请求体捕获器标记
+const BodyCaptureField = "ctx_body_capture"
+
+// 默认输出的请求体最大字节数
+const DefaultBodyLimit = 4096
+
+var bodyLimit = DefaultBodyLimit
+
+// 不输出请求体的 Content-Type, 包含其中任意一个即可
+var bodySkipContentTypes = []string{"multipart/", "octet-stream", "image/", "audio/", "video/", "zip", "pdf", "protobuf"}
+
+// 设置输出的请求体最大字节数, 超出的部分会被截断, 如果n<=0则不限制
+func SetBodyLimit(n int) {
+    bodyLimit = n
+}
+
+// 设置不输出请求体的 Content-Type, 请求的 Content-Type 包含其中任意一个即可
+func SetBodySkipContentTypes(types ...string) {
+    bodySkipContentTypes = append(([]string)(nil), types...)
+}
+
+// 请求体捕获器, 在请求体被读取时保存前面的数据
+type bodyCapture struct {
+    io.ReadCloser
+    buf   bytes.Buffer
+    limit int
+    total int
+    eof   bool
+}
+
+func (m *bodyCapture) Read(p []byte) (int, error) {
+    n, err := m.ReadCloser.Read(p)
+    if n > 0 {
+        if m.limit <= 0 {
+            m.buf.Write(p[:n])
+        } else if remain := m.limit + 1 - m.buf.Len(); remain > 0 {
+            if remain > n {
+                remain = n
+            }
+            m.buf.Write(p[:remain])
+        }
+        m.total += n
+    }
+    if err == io.EOF {
+        m.eof = true
+    }
+    return n, err
+}
+
+type multiReadCloser struct {
+    io.Reader
+    io.Closer
+}
+
+// 捕获请求体, 必须在读取请求体之前调用
+// 请求体被读取时会保存前面的数据用于输出, 不会重复缓存整个请求体, 也不需要设置 iris.WithoutBodyConsumptionOnUnmarshal
+func CaptureBody(ctx iris.Context) {
+    if ctx.Values().Get(BodyCaptureField) != nil {
+        return
+    }
+    body := ctx.Request().Body
+    if body == nil || isAllowContentType(ctx.GetHeader("Content-Type"), bodySkipContentTypes) {
+        return
+    }
+    c := &bodyCapture{ReadCloser: body, limit: bodyLimit}
+    ctx.Request().Body = c
+    ctx.Values().Set(BodyCaptureField, c)
+}
+
+// 获取经过脱敏的请求体, 超出限制的部分会被截断, 被跳过的 Content-Type 只会输出类型和长度
+func getBody(ctx iris.Context) string {
+    req := ctx.Request()
+    if contentType := ctx.GetHeader("Content-Type"); isAllowContentType(contentType, bodySkipContentTypes) {
+        if req.ContentLength > 0 {
+            return "(" + contentType + ", " + strconv.FormatInt(req.ContentLength, 10) + " bytes)"
+        }
+        return "(" + contentType + ")"
+    }
+
+    c, _ := ctx.Values().Get(BodyCaptureField).(*bodyCapture)
+    if c == nil {
+        if req.Body == nil {
+            return ""
+        }
+        c = &bodyCapture{ReadCloser: req.Body, limit: bodyLimit}
+        req.Body = c
+        ctx.Values().Set(BodyCaptureField, c)
+    }
+
+    // 请求体还没有读取到足够的数据, 读取一部分后放回去
+    if !c.eof && (c.limit <= 0 || c.buf.Len() <= c.limit) {
+        var extra bytes.Buffer
+        var err error
+        if c.limit <= 0 {
+            _, err = extra.ReadFrom(c)
+        } else {
+            _, err = io.CopyN(&extra, c, int64(c.limit+1-c.buf.Len()))
+        }
+        if err != nil && err != io.EOF {
+            return ""
+        }
+        req.Body = &multiReadCloser{Reader: io.MultiReader(&extra, req.Body), Closer: req.Body}
+    }
+
+    contentType := ctx.GetHeader("Content-Type")
+    body := c.buf.Bytes()
+    if c.limit <= 0 || len(body) <= c.limit {
+        return string(RedactBody(contentType, body))
+    }
+    total := int64(c.total)
+    if !c.eof {
+        total = req.ContentLength
+    }
+    if total > 0 {
+        return string(RedactBody(contentType, body[:c.limit])) + "...(truncated, total " + strconv.FormatInt(total, 10) + " bytes)"
+    }
+    return string(RedactBody(contentType, body[:c.limit])) + "...(truncated)"
+}
+
+// 获取经过脱敏和截断的请求体, 和样式标记 %(body)s 的输出相同
+func GetBody(ctx iris.Context) string {
+    return getBody(ctx)
+}
@@ -0,0 +1,145 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "io/ioutil"
+    "net/http/httptest"
+    "strconv"
+    "strings"
+    "testing"
+
+    "github.com/kataras/iris/v12"
+)
+
+// 使用app处理一个带请求体的请求, before在处理程序之前调用, after在处理程序之后调用
+func testBodyRequest(t *testing.T, contentType, body string, contentLength int64, handler iris.Handler, before, after func(ctx iris.Context)) {
+    app := iris.New()
+    app.Use(func(ctx iris.Context) {
+        CaptureBody(ctx)
+        if before != nil {
+            before(ctx)
+        }
+        ctx.Next()
+        if after != nil {
+            after(ctx)
+        }
+    })
+    app.Post("/", handler)
+    if err := app.Build(); err != nil {
+        t.Fatal(err)
+    }
+    req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+    req.ContentLength = contentLength
+    if contentType != "" {
+        req.Header.Set("Content-Type", contentType)
+    }
+    app.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestBodyAfterRead(t *testing.T) {
+    defer SetBodyLimit(DefaultBodyLimit)
+    SetBodyLimit(10)
+
+    const body = `{"a":"0123456789"}`
+    var read string
+    testBodyRequest(t, "application/json", body, int64(len(body)), func(ctx iris.Context) {
+        bs, _ := ioutil.ReadAll(ctx.Request().Body)
+        read = string(bs)
+    }, nil, func(ctx iris.Context) {
+        if s := GetBody(ctx); s != `{"a":"0123...(truncated, total 18 bytes)` {
+            t.Fatal("输出错误", s)
+        }
+    })
+    if read != body {
+        t.Fatal("处理程序读取的请求体错误", read)
+    }
+}
+
+func TestBodyBeforeRead(t *testing.T) {
+    defer SetBodyLimit(DefaultBodyLimit)
+
+    const body = `{"a":"0123456789"}`
+    for _, limit := range []int{10, 0, 100} {
+        SetBodyLimit(limit)
+
+        var read string
+        var logged string
+        testBodyRequest(t, "application/json", body, int64(len(body)), func(ctx iris.Context) {
+            bs, _ := ioutil.ReadAll(ctx.Request().Body)
+            read = string(bs)
+        }, func(ctx iris.Context) {
+            logged = GetBody(ctx)
+        }, nil)
+
+        if read != body {
+            t.Fatal(limit, "在输出日志后处理程序应该读取到完整的请求体", read)
+        }
+        expect := body
+        if limit == 10 {
+            expect = `{"a":"0123...(truncated, total 18 bytes)`
+        }
+        if logged != expect {
+            t.Fatal(limit, "输出错误", logged)
+        }
+    }
+}
+
+func TestBodyChunked(t *testing.T) {
+    defer SetBodyLimit(DefaultBodyLimit)
+    SetBodyLimit(4)
+
+    const body = "a=0123456789"
+    // 处理程序没有读取请求体时无法得知总长度
+    var read string
+    testBodyRequest(t, "text/plain", body, -1, func(ctx iris.Context) {}, func(ctx iris.Context) {
+        if s := GetBody(ctx); s != "a=01...(truncated)" {
+            t.Fatal("输出错误", s)
+        }
+    }, func(ctx iris.Context) {
+        bs, _ := ioutil.ReadAll(ctx.Request().Body)
+        read = string(bs)
+    })
+    if read != body {
+        t.Fatal("读取的请求体错误", read)
+    }
+
+    // 处理程序读取完请求体后使用实际读取的长度
+    testBodyRequest(t, "text/plain", body, -1, func(ctx iris.Context) {
+        _, _ = ioutil.ReadAll(ctx.Request().Body)
+    }, nil, func(ctx iris.Context) {
+        if s := GetBody(ctx); s != "a=01...(truncated, total 12 bytes)" {
+            t.Fatal("输出错误", s)
+        }
+    })
+}
+
+func TestBodySkipContentType(t *testing.T) {
+    const contentType = "multipart/form-data; boundary=x"
+    const body = "--x\r\nContent-Disposition: form-data; name=\"a\"\r\n\r\n1\r\n--x--\r\n"
+    testBodyRequest(t, contentType, body, int64(len(body)), func(ctx iris.Context) {
+        if ctx.FormValue("a") != "1" {
+            t.Fatal("处理程序读取的表单错误")
+        }
+    }, func(ctx iris.Context) {
+        if ctx.Values().Get(BodyCaptureField) != nil {
+            t.Fatal("跳过的 Content-Type 不应该捕获请求体")
+        }
+    }, func(ctx iris.Context) {
+        if s := GetBody(ctx); s != "("+contentType+", "+strconv.Itoa(len(body))+" bytes)" {
+            t.Fatal("输出错误", s)
+        }
+    })
+
+    testBodyRequest(t, "application/octet-stream", "abc", -1, func(ctx iris.Context) {}, nil, func(ctx iris.Context) {
+        if s := GetBody(ctx); s != "(application/octet-stream)" {
+            t.Fatal("输出错误", s)
+        }
+    })
+}
@@ -9,19 +9,13 @@
 package ctx_info
 
 import (
-    "bytes"
-    "encoding/json"
-    "fmt"
-    "io/ioutil"
+    "net/http"
     "regexp"
-    "strconv"
     "strings"
     "time"
 
     "github.com/kataras/iris/v12"
     "github.com/zlyuancn/zmap"
-
-    "github.com/zlyuancn/ziris"
 )
 
 // 开始时间标记
@@ -30,209 +24,185 @@ const StartTimeField = "ctx_start"
 const DefaultLayout = "[%(cstatus)s] %(clatency)s %(ip)s %(cmethod)s %(fullpath)s%(brbody)s"
 const DefaultLayoutWithHeader = "[%(cstatus)s] %(clatency)s %(ip)s %(cmethod)s %(fullpath)s%(brheader)s%(brbody)s"
 
-var defaultLayout = DefaultLayout
+// 默认样式, 在注册内置标记之后编译
+var defaultLayout *Layout
 
 type FormatFlag string
 
 const (
     // http状态码
     StatusFlag FormatFlag = "status"
-    // 彩色的http状态码
+    // 彩色的http状态码, 颜色由主题决定
     CStatusFlag FormatFlag = "cstatus"
-    // 延迟时间(处理时间)
+    // 延迟时间(处理时间), 可以指定单位, 如 %(latency:ms)s, 单位可以是 ns, us, ms, s
     LatencyFlag FormatFlag = "latency"
-    // 彩色的延迟时间(处理时间), 达到1秒黄色, 达到2秒红色
+    // 彩色的延迟时间(处理时间), 颜色由主题或路由的延迟时间区间决定, 默认达到1秒黄色, 达到2秒红色
     CLatencyFlag FormatFlag = "clatency"
-    // 客户端ip
+    // 客户端ip, 使用 ziris.SetTrustedProxies 设置可信代理后会从 X-Forwarded-For 等header中读取
+    // 没有设置可信代理时使用 iris 的 RemoteAddrHeaders 配置, 见 ziris.ClientIP
     IPFlag FormatFlag = "ip"
     // 请求方法
     MethodFlag FormatFlag = "method"
-    // 彩色的请求方法
+    // 彩色的请求方法, 颜色由主题决定
     CMethodFlag FormatFlag = "cmethod"
     // 请求路径
     PathFlag FormatFlag = "path"
     // 请求路径和请求参数(get参数)
     FullPathFlag FormatFlag = "fullpath"
-    // 请求体, 注意设置 iris.WithoutBodyConsumptionOnUnmarshal 选项, 否则无法读出body
+    // 请求体, 超出 SetBodyLimit 的部分会被截断
+    // 注意在读取请求体之前调用 CaptureBody(LogMiddleware会自动调用), 或者设置 iris.WithoutBodyConsumptionOnUnmarshal 选项, 否则无法读出body
     BodyFlag FormatFlag = "body"
     // 和BodyFlag相同, 但是在输出body之前会输出换行符号"\n"
     BrBodyFlag FormatFlag = "brbody"
-    // header
+    // get参数, 可以指定参数名, 如 %(query:page)s
+    QueryFlag FormatFlag = "query"
+    // header, 可以指定header名, 如 %(header:User-Agent)s
     HeaderFlag FormatFlag = "header"
     // 和HeaderFlag相同, 但是在输出header之前会输出换行符号"\n"
     BrHeaderFlag FormatFlag = "brheader"
+    // 请求id, 需要使用 RequestIDMiddleware 或 EnsureRequestID 设置
+    RequestIDFlag FormatFlag = "request_id"
+    // 响应体, 需要在处理请求之前使用 RecordResponse 记录响应, LogMiddleware 会自动记录
+    RespBodyFlag FormatFlag = "resp_body"
+    // 和RespBodyFlag相同, 但是在输出响应体之前会输出换行符号"\n"
+    BrRespBodyFlag FormatFlag = "brresp_body"
+    // 响应header, 可以指定header名, 如 %(resp_header:Content-Type)s
+    RespHeaderFlag FormatFlag = "resp_header"
+    // 和RespHeaderFlag相同, 但是在输出响应header之前会输出换行符号"\n"
+    BrRespHeaderFlag FormatFlag = "brresp_header"
+    // 响应大小(字节), %(resp_size:clf)s 在大小为0时输出 -
+    RespSizeFlag FormatFlag = "resp_size"
+    // 请求开始时间, 可以指定格式, 如 %(time:rfc3339)s, %(time:2006-01-02 15:04:05)s, 默认格式见 SetTimeFormat
+    TimeFlag FormatFlag = "time"
+    // 延迟时间的毫秒数(整数)
+    LatencyMsFlag FormatFlag = "latency_ms"
+    // Referer, 没有时为 -, %(referer:w3c)s 会将空格替换为+, 否则会转义双引号, 其中的get参数会按照 SetRedaction 的配置脱敏
+    RefererFlag FormatFlag = "referer"
+    // User-Agent, 没有时为 -, %(user_agent:w3c)s 会将空格替换为+, 否则会转义双引号
+    UserAgentFlag FormatFlag = "user_agent"
+    // 协议, 如 HTTP/1.1
+    ProtoFlag FormatFlag = "proto"
+    // basic auth 的用户名, 没有时为 -, %(remote_user:w3c)s 会将空格替换为+, 否则会转义双引号
+    RemoteUserFlag FormatFlag = "remote_user"
+    // 所有阶段耗时, 如 middleware=1.2ms handler=3ms, 可以指定单位, 如 %(timings:ms)s, 阶段使用 Mark 标记
+    TimingsFlag FormatFlag = "timings"
+    // 指定阶段的耗时, 可以指定单位, 如 %(timing:handler)s, %(timing:handler,ms)s, 没有该阶段时为空
+    TimingFlag FormatFlag = "timing"
 )
 
-var formatParser = regexp.MustCompile(`%\(.*?\)s`)
+// 样式标记格式为 %(name)s, 支持修饰符 %(name:arg)s, %(name)10s, %(name)-10s 和 %(name:max=80)s
+var formatParser = regexp.MustCompile(`%\(([^)]*)\)(-?\d*)s`)
 
 var headerFilters = []zmap.MapFilter{}
 
-// 设置开始时间, 将当前时间戳放入 ctx.Values() 的 StartTimeField 字段中
+// 设置开始时间, 将当前时间(包含单调时钟读数)放入 ctx.Values() 的 StartTimeField 字段中
+// 如果已经设置过开始时间则不会覆盖并返回false, 嵌套的中间件都可以调用它, 开始时间以最外层为准
 func SetStartTime(ctx iris.Context) bool {
-    return SetStartTimeOf(ctx, time.Now().UnixNano())
+    if ctx.Values().Get(StartTimeField) != nil {
+        return false
+    }
+    return SetStartTimeOf(ctx, time.Now())
 }
 
-// 设置指定的开始时间(纳秒)
+// 设置指定的开始时间, 可以是 time.Time, *time.Time, 或者纳秒时间戳(int, int64, uint, uint64, time.Duration)
+// 它会覆盖已经设置的开始时间
+// 注意, 使用时间戳时会按墙上时钟计算延迟时间, 可能受到系统时间调整的影响
 func SetStartTimeOf(ctx iris.Context, a interface{}) bool {
     _, b := ctx.Values().SetImmutable(StartTimeField, a)
     return b
 }
 
-// 获取延迟时间
-func GetLatency(ctx iris.Context) time.Duration {
-    latency := time.Duration(-1)
-
-    if start_time := ctx.Values().Get(StartTimeField); start_time != nil {
-        endTime := time.Duration(time.Now().UnixNano())
-        start := time.Duration(-1)
-
-        switch v := start_time.(type) {
-        case int:
-            start = time.Duration(v)
-        case int64:
-            start = time.Duration(v)
-
-        case uint:
-            start = time.Duration(v)
-        case uint64:
-            start = time.Duration(v)
-
-        case time.Duration:
-            start = v
-
-        case time.Time:
-            start = time.Duration(v.UnixNano())
-        case *time.Time:
-            start = time.Duration(v.UnixNano())
+// 获取开始时间, 未设置或类型不支持时返回false
+func getStartTime(ctx iris.Context) (time.Time, bool) {
+    switch v := ctx.Values().Get(StartTimeField).(type) {
+    case time.Time:
+        return v, true
+    case *time.Time:
+        if v != nil {
+            return *v, true
         }
+    case int:
+        return time.Unix(0, int64(v)), true
+    case int64:
+        return time.Unix(0, v), true
+    case uint:
+        return time.Unix(0, int64(v)), true
+    case uint64:
+        return time.Unix(0, int64(v)), true
+    case time.Duration:
+        return time.Unix(0, int64(v)), true
+    }
+    return time.Time{}, false
+}
 
-        if start != -1 {
-            latency = endTime - start
-        }
+// 获取延迟时间, 未设置开始时间或开始时间类型不支持时返回false
+func LookupLatency(ctx iris.Context) (time.Duration, bool) {
+    start, ok := getStartTime(ctx)
+    if !ok {
+        return 0, false
+    }
+    return time.Since(start), true
+}
+
+// 获取延迟时间, 未设置开始时间或开始时间类型不支持时返回-1, 需要区分时使用 LookupLatency
+func GetLatency(ctx iris.Context) time.Duration {
+    if latency, ok := LookupLatency(ctx); ok {
+        return latency
     }
-    return latency
+    return -1
 }
 
 // 获取描述信息
 func GetInfo(ctx iris.Context) string {
-    return GetInfoOfLayout(ctx, defaultLayout)
+    return defaultLayout.Render(ctx)
 }
 
-// 获取描述信息并指定样式
+// 获取描述信息并指定样式, 样式会被编译并缓存, 未注册的标记会输出为 (%(flag)s)invalid)
 func GetInfoOfLayout(ctx iris.Context, layout string) string {
-    s := formatParser.ReplaceAllStringFunc(layout, func(flag string) string {
-        flag = flag[2 : len(flag)-2]
-        switch FormatFlag(flag) {
-        case StatusFlag:
-            return strconv.Itoa(ctx.GetStatusCode())
-        case CStatusFlag:
-            code := ctx.GetStatusCode()
-            code_text := strconv.Itoa(code)
-            if code < 200 {
-                return ziris.MakeColorText(ziris.ColorBlue, code_text)
-            } else if code < 300 {
-                return ziris.MakeColorText(ziris.ColorCyan, code_text)
-            } else if code < 400 {
-                return ziris.MakeColorText(ziris.ColorYellow, code_text)
-            } else if code < 600 {
-                return ziris.MakeColorText(ziris.ColorRed, code_text)
-            }
-            return code_text
-        case LatencyFlag:
-            return GetLatency(ctx).String()
-        case CLatencyFlag:
-            latency := GetLatency(ctx)
-            latency_text := latency.String()
-            if latency < 1e9 {
-                return latency_text
-            } else if latency < 2e9 {
-                return ziris.MakeColorText(ziris.ColorYellow, latency_text)
-            }
-            return ziris.MakeColorText(ziris.ColorRed, latency_text)
-        case IPFlag:
-            return ctx.RemoteAddr()
-        case MethodFlag:
-            return ctx.Method()
-        case CMethodFlag:
-            method := ctx.Method()
-            switch strings.ToLower(method) {
-            case "get":
-                return ziris.MakeColorText(ziris.ColorCyan, method)
-            case "post":
-                return ziris.MakeColorText(ziris.ColorBlue, method)
-            case "put":
-                return ziris.MakeColorText(ziris.ColorYellow, method)
-            case "delete":
-                return ziris.MakeColorText(ziris.ColorRed, method)
-            case "head":
-                return ziris.MakeColorText(ziris.ColorYellow, method)
-            case "patch":
-                return ziris.MakeColorText(ziris.ColorYellow, method)
-            case "options":
-                return ziris.MakeColorText(ziris.ColorYellow, method)
-            }
-            return method
-        case PathFlag:
-            return ctx.Path()
-        case FullPathFlag:
-            return ctx.Request().URL.RequestURI()
-        case HeaderFlag, BrHeaderFlag:
-            var buff bytes.Buffer
-            if FormatFlag(flag) == BrHeaderFlag {
-                buff.WriteString("\n")
-            }
-
-            header := ctx.Request().Header
-            hm := make(zmap.M, len(header))
-            for k, v := range header {
-                switch len(v) {
-                case 1:
-                    hm[k] = v[0]
-                case 0:
-                    hm[k] = ""
-                default:
-                    hm[k] = v
-                }
-            }
-            hm.Filter(headerFilters...)
-
-            h, _ := json.MarshalIndent(hm, "", "    ")
-            buff.Write(h)
-
-            return buff.String()
-        case BodyFlag, BrBodyFlag:
-            body := ctx.Request().Body
-            if body == nil {
-                return ""
-            }
-
-            bodyCopy, _ := ioutil.ReadAll(body)
-            if len(bodyCopy) == 0 {
-                return ""
-            }
-
-            var buff bytes.Buffer
-            if FormatFlag(flag) == BrBodyFlag {
-                buff.WriteString("\n")
-            }
-            buff.Write(bodyCopy)
-
-            ctx.Request().Body = ioutil.NopCloser(bytes.NewBuffer(bodyCopy))
-            return buff.String()
+    return getCachedLayout(layout).Render(ctx)
+}
+
+// 获取经过脱敏和过滤的header
+func getHeader(ctx iris.Context) zmap.M {
+    return makeHeaderMap(ctx.Request().Header)
+}
+
+// 获取header的值, 需要脱敏的header会被替换, 多个值使用逗号连接
+func getHeaderValue(header http.Header, name string) string {
+    if redactHeaderName(name) {
+        if _, ok := header[http.CanonicalHeaderKey(name)]; ok {
+            return redaction.mask
+        }
+        return ""
+    }
+    return strings.Join(header[http.CanonicalHeaderKey(name)], ", ")
+}
+
+// 将header转为map, 需要脱敏的值会被替换, 然后使用header过滤器过滤
+func makeHeaderMap(header http.Header) zmap.M {
+    hm := make(zmap.M, len(header))
+    for k, v := range header {
+        if redactHeaderName(k) {
+            hm[k] = redaction.mask
+            continue
+        }
+        switch len(v) {
+        case 1:
+            hm[k] = v[0]
+        case 0:
+            hm[k] = ""
         default:
-            return fmt.Sprintf("(%%(%s)s)invalid)", flag)
+            hm[k] = v
         }
-    })
-    return s
+    }
+    hm.Filter(headerFilters...)
+    return hm
 }
 
 // 日志信息中间件, 用于输出当前请求信息
+// 如果log实现了 Warn(v ...interface{}) 或 Error(v ...interface{}) 会按状态码类别分级输出, 见 LogMiddlewareWithConfig
 func LogMiddleware(log interface{ Info(v ...interface{}) }) func(ctx iris.Context) {
-    return func(ctx iris.Context) {
-        SetStartTime(ctx)
-        ctx.Next()
-        log.Info(GetInfo(ctx))
-    }
+    return LogMiddlewareWithConfig(log, LogConfig{})
 }
 
 // 设置header过滤器
@@ -245,7 +215,26 @@ func AddHeaderFilter(filter ...zmap.MapFilter) {
     headerFilters = append(headerFilters, filter...)
 }
 
-// 设置默认样式
+// 设置默认样式, 未注册的标记会在输出时查找, 仍然未注册则输出为 (%(flag)s)invalid)
+// 需要在设置时检查样式请使用 SetDefaultLayoutStrict
 func SetDefaultLayout(layout string) {
+    defaultLayout, _ = compileLayout(layout, false)
+}
+
+// 设置默认样式, 样式中包含无效或未注册的标记时返回错误, 此时默认样式不会改变
+func SetDefaultLayoutStrict(layout string) error {
+    l, err := CompileLayout(layout)
+    if err != nil {
+        return err
+    }
+    defaultLayout = l
+    return nil
+}
+
+// 设置预编译的默认样式, layout不能为nil
+func SetDefaultCompiledLayout(layout *Layout) {
+    if layout == nil {
+        panic("默认样式不能为nil")
+    }
     defaultLayout = layout
 }
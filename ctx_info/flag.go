@@ -0,0 +1,202 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  样式标记
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "encoding/json"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/kataras/iris/v12"
+
+    "github.com/zlyuancn/ziris"
+)
+
+// 样式标记渲染函数
+type FormatFlagFunc func(ctx iris.Context) string
+
+// 带参数的样式标记渲染函数, arg为样式中标记名冒号后面的内容, 如 %(header:User-Agent)s 的arg为 User-Agent
+type FormatFlagArgFunc func(ctx iris.Context, arg string) string
+
+// 彩色样式标记渲染函数, 返回文本和颜色, 颜色为 ziris.ColorDefault 时不着色
+type ColorFormatFlagFunc func(ctx iris.Context) (string, ziris.ColorType)
+
+// 带参数的彩色样式标记渲染函数
+type ColorFormatFlagArgFunc func(ctx iris.Context, arg string) (string, ziris.ColorType)
+
+var formatFlags = make(map[FormatFlag]FormatFlagArgFunc)
+
+// 注册样式标记, 注册后可以在样式中使用 %(name)s, 如果标记已存在会替换它
+// 它不是并发安全的, 应该在初始化时调用
+func RegisterFormatFlag(name string, fn FormatFlagFunc) {
+    if fn == nil {
+        panic("样式标记渲染函数不能为空")
+    }
+    RegisterFormatFlagWithArg(name, func(ctx iris.Context, arg string) string {
+        return fn(ctx)
+    })
+}
+
+// 注册带参数的样式标记, 注册后可以在样式中使用 %(name)s 和 %(name:arg)s
+func RegisterFormatFlagWithArg(name string, fn FormatFlagArgFunc) {
+    if name == "" || fn == nil {
+        panic("样式标记名和渲染函数不能为空")
+    }
+    if strings.ContainsAny(name, ":)") {
+        panic("样式标记名不能包含 : 或 )")
+    }
+    formatFlags[FormatFlag(name)] = fn
+}
+
+// 注册彩色样式标记, 会同时注册 name 和 "c"+name 两个标记, 前者输出文本, 后者输出彩色文本
+// 如 RegisterColorFormatFlag("user", fn) 后可以在样式中使用 %(user)s 和 %(cuser)s
+func RegisterColorFormatFlag(name string, fn ColorFormatFlagFunc) {
+    if fn == nil {
+        panic("样式标记渲染函数不能为空")
+    }
+    RegisterColorFormatFlagWithArg(name, func(ctx iris.Context, arg string) (string, ziris.ColorType) {
+        return fn(ctx)
+    })
+}
+
+// 注册带参数的彩色样式标记, 会同时注册 name 和 "c"+name 两个标记
+func RegisterColorFormatFlagWithArg(name string, fn ColorFormatFlagArgFunc) {
+    if fn == nil {
+        panic("样式标记渲染函数不能为空")
+    }
+    RegisterFormatFlagWithArg(name, func(ctx iris.Context, arg string) string {
+        text, _ := fn(ctx, arg)
+        return text
+    })
+    RegisterFormatFlagWithArg("c"+name, func(ctx iris.Context, arg string) string {
+        text, color := fn(ctx, arg)
+        if color == ziris.ColorDefault {
+            return text
+        }
+        return ziris.MakeColorText(color, text)
+    })
+}
+
+// 检查样式标记是否已注册
+func HasFormatFlag(name string) bool {
+    _, ok := formatFlags[FormatFlag(name)]
+    return ok
+}
+
+// 注册一个样式标记和它的换行版本, 换行版本会在输出之前输出换行符号"\n"
+func registerBrFormatFlag(flag, brFlag FormatFlag, fn FormatFlagArgFunc, brWhenEmpty bool) {
+    RegisterFormatFlagWithArg(string(flag), fn)
+    RegisterFormatFlagWithArg(string(brFlag), func(ctx iris.Context, arg string) string {
+        s := fn(ctx, arg)
+        if s == "" && !brWhenEmpty {
+            return ""
+        }
+        return "\n" + s
+    })
+}
+
+// 按照单位格式化延迟时间, 单位可以是 ns, us, ms, s, 为空时使用 time.Duration 的格式
+func formatLatency(latency time.Duration, unit string) string {
+    switch unit {
+    case "ns":
+        return strconv.FormatInt(int64(latency), 10) + "ns"
+    case "us", "µs":
+        return strconv.FormatFloat(float64(latency)/float64(time.Microsecond), 'f', 3, 64) + "us"
+    case "ms":
+        return strconv.FormatFloat(float64(latency)/float64(time.Millisecond), 'f', 3, 64) + "ms"
+    case "s":
+        return strconv.FormatFloat(latency.Seconds(), 'f', 3, 64) + "s"
+    }
+    return latency.String()
+}
+
+func init() {
+    RegisterColorFormatFlag(string(StatusFlag), func(ctx iris.Context) (string, ziris.ColorType) {
+        code := ctx.GetStatusCode()
+        return strconv.Itoa(code), statusColor(code)
+    })
+    RegisterColorFormatFlagWithArg(string(LatencyFlag), func(ctx iris.Context, unit string) (string, ziris.ColorType) {
+        latency := GetLatency(ctx)
+        return formatLatency(latency, unit), latencyColor(ctx, latency)
+    })
+    RegisterColorFormatFlag(string(MethodFlag), func(ctx iris.Context) (string, ziris.ColorType) {
+        method := ctx.Method()
+        return method, methodColor(method)
+    })
+    RegisterFormatFlag(string(IPFlag), ziris.ClientIP)
+    RegisterFormatFlag(string(PathFlag), func(ctx iris.Context) string {
+        return ctx.Path()
+    })
+    RegisterFormatFlag(string(FullPathFlag), func(ctx iris.Context) string {
+        return RedactURI(ctx.Request().URL)
+    })
+    RegisterFormatFlag(string(RequestIDFlag), GetRequestID)
+    RegisterFormatFlagWithArg(string(QueryFlag), func(ctx iris.Context, name string) string {
+        if name == "" {
+            return redactQueryString(ctx.Request().URL.RawQuery, redaction.queryParams)
+        }
+        if _, ok := redaction.queryParams[name]; ok {
+            return redaction.mask
+        }
+        return ctx.URLParam(name)
+    })
+    registerBrFormatFlag(HeaderFlag, BrHeaderFlag, func(ctx iris.Context, name string) string {
+        if name != "" {
+            return getHeaderValue(ctx.Request().Header, name)
+        }
+        h, _ := json.MarshalIndent(getHeader(ctx), "", "    ")
+        return string(h)
+    }, true)
+    registerBrFormatFlag(BodyFlag, BrBodyFlag, func(ctx iris.Context, arg string) string {
+        return getBody(ctx)
+    }, false)
+    registerBrFormatFlag(RespHeaderFlag, BrRespHeaderFlag, func(ctx iris.Context, name string) string {
+        if name != "" {
+            return getHeaderValue(ctx.ResponseWriter().Header(), name)
+        }
+        h, _ := json.MarshalIndent(getRespHeader(ctx), "", "    ")
+        return string(h)
+    }, true)
+    registerBrFormatFlag(RespBodyFlag, BrRespBodyFlag, func(ctx iris.Context, arg string) string {
+        return getRespBody(ctx)
+    }, false)
+    RegisterFormatFlagWithArg(string(RespSizeFlag), func(ctx iris.Context, arg string) string {
+        size := getRespSize(ctx)
+        if size == 0 && arg == "clf" {
+            return "-"
+        }
+        return strconv.Itoa(size)
+    })
+    RegisterFormatFlagWithArg(string(TimeFlag), formatTime)
+    RegisterFormatFlag(string(LatencyMsFlag), func(ctx iris.Context) string {
+        return strconv.FormatInt(int64(GetLatency(ctx)/time.Millisecond), 10)
+    })
+    RegisterFormatFlagWithArg(string(RefererFlag), getReferer)
+    RegisterFormatFlagWithArg(string(UserAgentFlag), func(ctx iris.Context, mode string) string {
+        return getLogHeader(ctx, "User-Agent", mode)
+    })
+    RegisterFormatFlag(string(ProtoFlag), func(ctx iris.Context) string {
+        return ctx.Request().Proto
+    })
+    RegisterFormatFlagWithArg(string(RemoteUserFlag), getRemoteUser)
+    RegisterFormatFlagWithArg(string(TimingsFlag), formatTimings)
+    RegisterFormatFlagWithArg(string(TimingFlag), func(ctx iris.Context, arg string) string {
+        name, unit := arg, ""
+        if k := strings.Index(arg, ","); k != -1 {
+            name, unit = arg[:k], arg[k+1:]
+        }
+        if d, ok := GetTiming(ctx, name); ok {
+            return formatLatency(d, unit)
+        }
+        return ""
+    })
+
+    defaultLayout = MustCompileLayout(DefaultLayout)
+}
@@ -0,0 +1,124 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "testing"
+
+    "github.com/kataras/iris/v12"
+
+    "github.com/zlyuancn/ziris"
+)
+
+// 检查fn是否panic
+func testPanic(t *testing.T, name string, fn func()) {
+    defer func() {
+        if recover() == nil {
+            t.Fatal(name, "应该panic")
+        }
+    }()
+    fn()
+}
+
+func TestRegisterFormatFlagWithArg(t *testing.T) {
+    RegisterFormatFlagWithArg("test_param", func(ctx iris.Context, arg string) string {
+        if arg == "" {
+            return "none"
+        }
+        return ctx.URLParam(arg)
+    })
+    if !HasFormatFlag("test_param") || HasFormatFlag("test_not_exists") {
+        t.Fatal("HasFormatFlag 结果错误")
+    }
+
+    testRequest(t, "GET", "/a?x=1&y=2", func(ctx iris.Context) {}, func(ctx iris.Context) {
+        if s := GetInfoOfLayout(ctx, "%(test_param)s %(test_param:x)s %(test_param:y)s"); s != "none 1 2" {
+            t.Fatal("输出错误", s)
+        }
+    })
+
+    // 已存在的标记会被替换
+    RegisterFormatFlag("test_param", func(ctx iris.Context) string {
+        return "replaced"
+    })
+    testRequest(t, "GET", "/a?x=1", func(ctx iris.Context) {}, func(ctx iris.Context) {
+        if s := GetInfoOfLayout(ctx, "%(test_param:x)s"); s != "replaced" {
+            t.Fatal("输出错误", s)
+        }
+    })
+}
+
+func TestRegisterColorFormatFlag(t *testing.T) {
+    defer ziris.SetColorEnabled(ziris.IsColorEnabled())
+
+    RegisterColorFormatFlag("test_user", func(ctx iris.Context) (string, ziris.ColorType) {
+        user := ctx.URLParam("user")
+        if user == "admin" {
+            return user, ziris.ColorRed
+        }
+        return user, ziris.ColorDefault
+    })
+    RegisterColorFormatFlagWithArg("test_color_param", func(ctx iris.Context, arg string) (string, ziris.ColorType) {
+        return ctx.URLParam(arg), ziris.ColorGreen
+    })
+    for _, name := range []string{"test_user", "ctest_user", "test_color_param", "ctest_color_param"} {
+        if !HasFormatFlag(name) {
+            t.Fatal("没有注册标记", name)
+        }
+    }
+
+    ziris.SetColorEnabled(true)
+    testRequest(t, "GET", "/a?user=admin&x=1", func(ctx iris.Context) {}, func(ctx iris.Context) {
+        tests := []struct {
+            layout string
+            expect string
+        }{
+            {"%(test_user)s", "admin"},
+            {"%(ctest_user)s", "\x1b[31madmin\x1b[0m"},
+            {"%(test_color_param:x)s", "1"},
+            {"%(ctest_color_param:x)s", "\x1b[32m1\x1b[0m"},
+        }
+        for _, tt := range tests {
+            if s := GetInfoOfLayout(ctx, tt.layout); s != tt.expect {
+                t.Fatalf("%s 输出错误 %q", tt.layout, s)
+            }
+        }
+    })
+    // 默认颜色不着色
+    testRequest(t, "GET", "/a?user=guest", func(ctx iris.Context) {}, func(ctx iris.Context) {
+        if s := GetInfoOfLayout(ctx, "%(ctest_user)s"); s != "guest" {
+            t.Fatalf("输出错误 %q", s)
+        }
+    })
+    // 关闭颜色后不着色
+    ziris.SetColorEnabled(false)
+    testRequest(t, "GET", "/a?user=admin", func(ctx iris.Context) {}, func(ctx iris.Context) {
+        if s := GetInfoOfLayout(ctx, "%(ctest_user)s"); s != "admin" {
+            t.Fatalf("输出错误 %q", s)
+        }
+    })
+}
+
+func TestRegisterFormatFlagInvalid(t *testing.T) {
+    testPanic(t, "空的标记名", func() {
+        RegisterFormatFlag("", func(ctx iris.Context) string { return "" })
+    })
+    testPanic(t, "空的渲染函数", func() {
+        RegisterFormatFlag("test_nil", nil)
+    })
+    testPanic(t, "标记名包含冒号", func() {
+        RegisterFormatFlag("test:a", func(ctx iris.Context) string { return "" })
+    })
+    testPanic(t, "标记名包含括号", func() {
+        RegisterFormatFlag("test)a", func(ctx iris.Context) string { return "" })
+    })
+    testPanic(t, "空的彩色渲染函数", func() {
+        RegisterColorFormatFlag("test_nil", nil)
+    })
+}
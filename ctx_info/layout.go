@@ -0,0 +1,250 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  预编译样式
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+    "sync"
+    "sync/atomic"
+    "unicode/utf8"
+
+    "github.com/kataras/iris/v12"
+
+    "github.com/zlyuancn/ziris"
+)
+
+// 预编译的样式
+type Layout struct {
+    text     string
+    segments []layoutSegment
+    // 是否需要记录响应
+    needRecordResponse bool
+    // 上次输出的长度, 用于预分配缓冲区
+    lastSize int64
+}
+
+type layoutSegment struct {
+    // 字面量, flag为空时有效
+    literal string
+    flag    FormatFlag
+    // 标记参数
+    arg string
+    // 编译时标记未注册则为nil, 会在输出时查找
+    fn FormatFlagArgFunc
+    // 最小宽度, 为负数时左对齐
+    width int
+    // 最大宽度, 为0时不限制
+    max int
+}
+
+// 编译样式, 样式中包含未注册的标记时返回错误
+// 注意, 编译后再次注册同名标记不会影响已编译的样式
+func CompileLayout(layout string) (*Layout, error) {
+    return compileLayout(layout, true)
+}
+
+// 编译样式, 失败时panic
+func MustCompileLayout(layout string) *Layout {
+    l, err := CompileLayout(layout)
+    if err != nil {
+        panic(err)
+    }
+    return l
+}
+
+// 编译样式, 如果strict为false, 未注册的标记会在输出时查找, 仍然未注册则输出无效标记
+func compileLayout(layout string, strict bool) (*Layout, error) {
+    l := &Layout{text: layout}
+    last := 0
+    for _, loc := range formatParser.FindAllStringSubmatchIndex(layout, -1) {
+        if loc[0] > last {
+            l.segments = append(l.segments, layoutSegment{literal: layout[last:loc[0]]})
+        }
+        last = loc[1]
+
+        seg, err := parseSegment(layout[loc[2]:loc[3]], layout[loc[4]:loc[5]])
+        if err != nil {
+            if strict {
+                return nil, err
+            }
+            l.segments = append(l.segments, layoutSegment{literal: fmt.Sprintf("(%s)invalid)", layout[loc[0]:loc[1]])})
+            continue
+        }
+
+        seg.fn = formatFlags[seg.flag]
+        if seg.fn == nil && strict {
+            return nil, fmt.Errorf("未注册的样式标记: %s", layout[loc[0]:loc[1]])
+        }
+        l.segments = append(l.segments, seg)
+
+        if seg.flag == RespBodyFlag || seg.flag == BrRespBodyFlag {
+            l.needRecordResponse = true
+        }
+    }
+    if last < len(layout) {
+        l.segments = append(l.segments, layoutSegment{literal: layout[last:]})
+    }
+    return l, nil
+}
+
+// 解析标记, name为括号中的内容, 如 path:max=80, width为括号后面的宽度, 如 -10
+func parseSegment(name, width string) (layoutSegment, error) {
+    seg := layoutSegment{}
+    if width != "" && width != "-" {
+        w, err := strconv.Atoi(width)
+        if err != nil {
+            return seg, fmt.Errorf("无效的宽度: %s", width)
+        }
+        seg.width = w
+    }
+
+    if k := strings.Index(name, ":"); k != -1 {
+        name, seg.arg = name[:k], name[k+1:]
+
+        // 通用修饰符 max=N 放在参数末尾, 如 %(header:User-Agent,max=40)s
+        rest, last := "", seg.arg
+        if k := strings.LastIndex(seg.arg, ","); k != -1 {
+            rest, last = seg.arg[:k], seg.arg[k+1:]
+        }
+        if strings.HasPrefix(last, "max=") {
+            max, err := strconv.Atoi(last[4:])
+            if err != nil || max <= 0 {
+                return seg, fmt.Errorf("无效的修饰符: %s", last)
+            }
+            seg.max, seg.arg = max, rest
+        }
+    }
+    seg.flag = FormatFlag(name)
+    return seg, nil
+}
+
+// 返回样式文本
+func (m *Layout) String() string {
+    return m.text
+}
+
+// 根据样式输出描述信息
+func (m *Layout) Render(ctx iris.Context) string {
+    var sb strings.Builder
+    sb.Grow(int(atomic.LoadInt64(&m.lastSize)))
+    for i := range m.segments {
+        seg := &m.segments[i]
+        if seg.flag == "" {
+            sb.WriteString(seg.literal)
+            continue
+        }
+
+        fn := seg.fn
+        if fn == nil {
+            fn = formatFlags[seg.flag]
+        }
+        if fn == nil {
+            sb.WriteString(fmt.Sprintf("(%%(%s)s)invalid)", seg.flag))
+            continue
+        }
+
+        text := fn(ctx, seg.arg)
+        if seg.max > 0 {
+            text = truncateVisible(text, seg.max)
+        }
+        if seg.width != 0 {
+            text = padVisible(text, seg.width)
+        }
+        sb.WriteString(text)
+    }
+    atomic.StoreInt64(&m.lastSize, int64(sb.Len()))
+    return sb.String()
+}
+
+// 返回文本的可见宽度, 不包含颜色控制码
+func visibleLen(s string) int {
+    return utf8.RuneCountInString(ziris.StripColor(s))
+}
+
+// 跳过从i开始的控制码, 返回控制码之后的位置
+func skipEscape(s string, i int) int {
+    j := i + 1
+    if j < len(s) && s[j] == '[' {
+        j++
+        for j < len(s) && (s[j] < 0x40 || s[j] > 0x7e) {
+            j++
+        }
+        if j < len(s) {
+            j++
+        }
+    }
+    return j
+}
+
+// 将文本截断到最大可见宽度, 保留颜色控制码
+func truncateVisible(s string, max int) string {
+    if visibleLen(s) <= max {
+        return s
+    }
+    var sb strings.Builder
+    n := 0
+    for i := 0; i < len(s); {
+        if s[i] == '\x1b' {
+            j := skipEscape(s, i)
+            sb.WriteString(s[i:j])
+            i = j
+            continue
+        }
+        _, size := utf8.DecodeRuneInString(s[i:])
+        if n < max {
+            sb.WriteString(s[i : i+size])
+        }
+        i += size
+        n++
+    }
+    return sb.String()
+}
+
+// 使用空格将文本填充到指定宽度, 宽度为负数时左对齐
+func padVisible(s string, width int) string {
+    left := width < 0
+    if left {
+        width = -width
+    }
+    n := width - visibleLen(s)
+    if n <= 0 {
+        return s
+    }
+    if left {
+        return s + strings.Repeat(" ", n)
+    }
+    return strings.Repeat(" ", n) + s
+}
+
+// 缓存 GetInfoOfLayout 编译的样式
+var layoutCache sync.Map
+
+// 缓存的样式数量
+var layoutCacheSize int64
+
+// 最多缓存的样式数量, 避免动态生成的样式占用过多内存
+const maxLayoutCacheSize = 1024
+
+// 获取宽松编译的样式, 会缓存编译结果
+func getCachedLayout(layout string) *Layout {
+    if l, ok := layoutCache.Load(layout); ok {
+        return l.(*Layout)
+    }
+    l, _ := compileLayout(layout, false)
+    if atomic.LoadInt64(&layoutCacheSize) >= maxLayoutCacheSize {
+        return l
+    }
+    actual, loaded := layoutCache.LoadOrStore(layout, l)
+    if !loaded {
+        atomic.AddInt64(&layoutCacheSize, 1)
+    }
+    return actual.(*Layout)
+}
@@ -0,0 +1,119 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "net/http/httptest"
+    "testing"
+    "time"
+
+    "github.com/kataras/iris/v12"
+
+    "github.com/zlyuancn/ziris"
+)
+
+// 使用app处理一个请求, 在请求处理完毕后调用fn
+func testRequest(t *testing.T, method, target string, handler iris.Handler, fn func(ctx iris.Context)) {
+    app := iris.New()
+    app.Use(func(ctx iris.Context) {
+        SetStartTime(ctx)
+        CaptureBody(ctx)
+        ctx.Next()
+        fn(ctx)
+    })
+    app.Handle(method, "/{any:path}", handler)
+    if err := app.Build(); err != nil {
+        t.Fatal(err)
+    }
+    app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, target, nil))
+}
+
+func TestCompileLayout(t *testing.T) {
+    if _, err := CompileLayout("%(status)s %(not_exists)s"); err == nil {
+        t.Fatal("未注册的标记应该返回错误")
+    }
+
+    l, err := CompileLayout("[%(status)s] %(method)s %(fullpath)s")
+    if err != nil {
+        t.Fatal(err)
+    }
+    testRequest(t, "GET", "/a?b=1", func(ctx iris.Context) {
+        ctx.StatusCode(201)
+    }, func(ctx iris.Context) {
+        if s := l.Render(ctx); s != "[201] GET /a?b=1" {
+            t.Fatal("输出错误", s)
+        }
+        if s := GetInfoOfLayout(ctx, "%(method)s %(not_exists)s"); s != "GET (%(not_exists)s)invalid)" {
+            t.Fatal("输出错误", s)
+        }
+    })
+}
+
+func TestRegisterFormatFlag(t *testing.T) {
+    RegisterFormatFlag("test_tenant", func(ctx iris.Context) string {
+        return ctx.URLParam("tenant")
+    })
+    l := MustCompileLayout("%(path)s %(test_tenant)s")
+    testRequest(t, "GET", "/a?tenant=acme", func(ctx iris.Context) {}, func(ctx iris.Context) {
+        if s := l.Render(ctx); s != "/a acme" {
+            t.Fatal("输出错误", s)
+        }
+    })
+}
+
+func TestLayoutModifier(t *testing.T) {
+    defer ziris.SetColorEnabled(ziris.IsColorEnabled())
+    ziris.SetColorEnabled(true)
+
+    l := MustCompileLayout("[%(status)5s][%(method)-5s][%(path:max=4)s][%(query:page)s][%(header:X-Test)s][%(cstatus)4s]")
+    testRequest(t, "GET", "/abcdef?page=3", func(ctx iris.Context) {}, func(ctx iris.Context) {
+        ctx.Request().Header.Set("X-Test", "v")
+        expect := "[  200][GET  ][/abc][3][v][ " + "\x1b[36m200\x1b[0m]"
+        if s := l.Render(ctx); s != expect {
+            t.Fatalf("输出错误 %q", s)
+        }
+    })
+
+    if _, err := CompileLayout("%(path:max=x)s"); err == nil {
+        t.Fatal("无效的修饰符应该返回错误")
+    }
+    if s := formatLatency(1500*time.Microsecond, "ms"); s != "1.500ms" {
+        t.Fatal("延迟时间格式化错误", s)
+    }
+}
+
+func TestSetDefaultLayout(t *testing.T) {
+    defer SetDefaultCompiledLayout(defaultLayout)
+
+    if err := SetDefaultLayoutStrict("%(status)s %(not_exists)s"); err == nil {
+        t.Fatal("未注册的标记应该返回错误")
+    }
+    if err := SetDefaultLayoutStrict("%(path:max=x)s"); err == nil {
+        t.Fatal("无效的修饰符应该返回错误")
+    }
+    if err := SetDefaultLayoutStrict("%(method)s %(path)s"); err != nil {
+        t.Fatal(err)
+    }
+    testRequest(t, "GET", "/a", func(ctx iris.Context) {}, func(ctx iris.Context) {
+        if s := GetInfo(ctx); s != "GET /a" {
+            t.Fatal("输出错误", s)
+        }
+    })
+
+    SetDefaultLayout("%(method)s %(not_exists)s")
+    testRequest(t, "GET", "/a", func(ctx iris.Context) {}, func(ctx iris.Context) {
+        if s := GetInfo(ctx); s != "GET (%(not_exists)s)invalid)" {
+            t.Fatal("输出错误", s)
+        }
+    })
+
+    testPanic(t, "设置nil的默认样式", func() {
+        SetDefaultCompiledLayout(nil)
+    })
+}
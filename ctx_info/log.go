@@ -0,0 +1,195 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  日志过滤和采样
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "math/rand"
+    "time"
+
+    "github.com/kataras/iris/v12"
+)
+
+// 日志级别
+type LogLevel int
+
+const (
+    LevelInfo LogLevel = iota
+    LevelWarn
+    LevelError
+)
+
+// 分级日志记录器
+type Logger interface {
+    Info(v ...interface{})
+    Warn(v ...interface{})
+    Error(v ...interface{})
+}
+
+// 只实现了Info的日志记录器, Warn和Error会回退到Info
+type infoLogger struct {
+    log interface{ Info(v ...interface{}) }
+}
+
+func (m infoLogger) Info(v ...interface{}) { m.log.Info(v...) }
+func (m infoLogger) Warn(v ...interface{}) {
+    if l, ok := m.log.(interface{ Warn(v ...interface{}) }); ok {
+        l.Warn(v...)
+        return
+    }
+    m.log.Info(v...)
+}
+func (m infoLogger) Error(v ...interface{}) {
+    if l, ok := m.log.(interface{ Error(v ...interface{}) }); ok {
+        l.Error(v...)
+        return
+    }
+    m.log.Info(v...)
+}
+
+// 转为分级日志记录器, 没有实现的级别使用Info输出
+func toLogger(log interface{ Info(v ...interface{}) }) Logger {
+    if l, ok := log.(Logger); ok {
+        return l
+    }
+    return infoLogger{log}
+}
+
+// 按级别输出日志
+func logWithLevel(log Logger, level LogLevel, v ...interface{}) {
+    switch level {
+    case LevelWarn:
+        log.Warn(v...)
+    case LevelError:
+        log.Error(v...)
+    default:
+        log.Info(v...)
+    }
+}
+
+// 默认的状态码类别的日志级别, key为状态码除以100, 如 4 表示 4xx
+var DefaultStatusLevels = map[int]LogLevel{
+    2: LevelInfo,
+    3: LevelInfo,
+    4: LevelWarn,
+    5: LevelError,
+}
+
+// 日志中间件配置
+type LogConfig struct {
+    // 样式, 为空时使用默认样式, 见 SetDefaultLayout, 包含未注册的标记时会panic
+    Layout string
+    // 跳过的路径, 完全匹配, 如健康检查 /health
+    SkipPaths []string
+    // 跳过的路径前缀, 按路径段匹配, 如 /static 匹配 /static/a.js, 但不匹配 /statics
+    SkipPrefixes []string
+    // 状态码小于400的请求的采样率, 范围为(0, 1], 为0时全部记录, 为负数时不记录
+    // 状态码大于等于400的请求和慢请求总是记录
+    SampleRate float64
+    // 慢请求阈值, 为0时使用 DefaultSlowThreshold, 路由的阈值见 SetRouteSlowThreshold
+    SlowThreshold time.Duration
+    // 慢请求的日志级别, 为nil时使用 LevelWarn, 状态码类别的级别更高时使用状态码类别的级别
+    SlowLevel *LogLevel
+    // 状态码类别的日志级别, key为状态码除以100, 如 4 表示 4xx, 未设置的类别使用 DefaultStatusLevels
+    StatusLevels map[int]LogLevel
+}
+
+// 编译后的跳过路径配置
+type logSkipper struct {
+    paths    map[string]struct{}
+    prefixes routePrefixes
+}
+
+func newLogSkipper(conf *LogConfig) *logSkipper {
+    m := &logSkipper{
+        paths:    make(map[string]struct{}, len(conf.SkipPaths)),
+        prefixes: newRoutePrefixes(conf.SkipPrefixes),
+    }
+    for _, p := range conf.SkipPaths {
+        m.paths[p] = struct{}{}
+    }
+    return m
+}
+
+// 检查是否跳过路径
+func (m *logSkipper) isSkip(path string) bool {
+    if _, ok := m.paths[path]; ok {
+        return true
+    }
+    _, ok := m.prefixes.match(path)
+    return ok
+}
+
+// 获取状态码的日志级别
+func (m *LogConfig) statusLevel(status int) LogLevel {
+    if level, ok := m.StatusLevels[status/100]; ok {
+        return level
+    }
+    if level, ok := DefaultStatusLevels[status/100]; ok {
+        return level
+    }
+    return LevelInfo
+}
+
+// 按照采样率检查是否记录, 见 LogConfig.SampleRate
+func sampled(rate float64) bool {
+    switch {
+    case rate < 0:
+        return false
+    case rate == 0 || rate >= 1:
+        return true
+    }
+    return rand.Float64() < rate
+}
+
+// 带过滤和采样的日志中间件
+// 如果log实现了 Warn(v ...interface{}) 或 Error(v ...interface{}) 会按级别输出, 否则使用Info输出
+func LogMiddlewareWithConfig(log interface{ Info(v ...interface{}) }, conf LogConfig) func(ctx iris.Context) {
+    logger := toLogger(log)
+    if conf.SlowThreshold <= 0 {
+        conf.SlowThreshold = DefaultSlowThreshold
+    }
+    slowLevel := LevelWarn
+    if conf.SlowLevel != nil {
+        slowLevel = *conf.SlowLevel
+    }
+    var layout *Layout
+    if conf.Layout != "" {
+        layout = MustCompileLayout(conf.Layout)
+    }
+    skipper := newLogSkipper(&conf)
+
+    return func(ctx iris.Context) {
+        if skipper.isSkip(ctx.Path()) {
+            ctx.Next()
+            return
+        }
+
+        l := layout
+        if l == nil {
+            l = defaultLayout
+        }
+        SetStartTime(ctx)
+        CaptureBody(ctx)
+        if l.needRecordResponse {
+            RecordResponse(ctx)
+        }
+        ctx.Next()
+
+        status := ctx.GetStatusCode()
+        level := conf.statusLevel(status)
+        if GetLatency(ctx) >= getSlowThreshold(ctx, conf.SlowThreshold) {
+            if slowLevel > level {
+                level = slowLevel
+            }
+        } else if status < 400 && !sampled(conf.SampleRate) {
+            return
+        }
+        logWithLevel(logger, level, l.Render(ctx))
+    }
+}
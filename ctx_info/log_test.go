@@ -0,0 +1,104 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "net/http/httptest"
+    "testing"
+    "time"
+
+    "github.com/kataras/iris/v12"
+)
+
+type testLevelLogger map[string][]string
+
+func (m testLevelLogger) Info(v ...interface{})  { m["info"] = append(m["info"], v[0].(string)) }
+func (m testLevelLogger) Warn(v ...interface{})  { m["warn"] = append(m["warn"], v[0].(string)) }
+func (m testLevelLogger) Error(v ...interface{}) { m["error"] = append(m["error"], v[0].(string)) }
+
+func TestLogMiddlewareWithConfig(t *testing.T) {
+    log := testLevelLogger{}
+    app := iris.New()
+    app.Use(LogMiddlewareWithConfig(log, LogConfig{
+        Layout:       "%(status)s %(path)s",
+        SkipPaths:    []string{"/health"},
+        SkipPrefixes: []string{"/static"},
+        SampleRate:   0.000001,
+    }))
+    app.Get("/{any:path}", func(ctx iris.Context) {
+        switch ctx.Path() {
+        case "/bad", "/statics":
+            ctx.StatusCode(400)
+        case "/err":
+            ctx.StatusCode(500)
+        }
+    })
+    if err := app.Build(); err != nil {
+        t.Fatal(err)
+    }
+    for _, path := range []string{"/health", "/static/a.js", "/ok", "/bad", "/err", "/statics"} {
+        app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+    }
+
+    if len(log["info"]) != 0 || len(log["warn"]) != 2 || len(log["error"]) != 1 {
+        t.Fatal("日志过滤错误", log)
+    }
+    if log["warn"][0] != "400 /bad" || log["warn"][1] != "400 /statics" || log["error"][0] != "500 /err" {
+        t.Fatal("日志级别错误", log)
+    }
+}
+
+func TestLogMiddlewareWithConfigUnknownFlag(t *testing.T) {
+    testPanic(t, "未注册的标记", func() {
+        LogMiddlewareWithConfig(testLevelLogger{}, LogConfig{Layout: "%(status)s %(unknown_flag)s"})
+    })
+}
+
+func TestLogSlowLevelAndSampleRate(t *testing.T) {
+    levelInfo, levelError := LevelInfo, LevelError
+    tests := []struct {
+        name   string
+        conf   LogConfig
+        path   string
+        expect string
+    }{
+        {"不记录成功的请求", LogConfig{SampleRate: -1, SlowThreshold: time.Hour}, "/ok", ""},
+        {"不记录成功的请求时仍然记录错误", LogConfig{SampleRate: -1, SlowThreshold: time.Hour}, "/bad", "warn"},
+        {"不记录成功的请求时仍然记录慢请求", LogConfig{SampleRate: -1, SlowThreshold: time.Nanosecond}, "/ok", "warn"},
+        {"全部记录", LogConfig{SlowThreshold: time.Hour}, "/ok", "info"},
+        {"慢请求默认使用warn", LogConfig{SlowThreshold: time.Nanosecond}, "/ok", "warn"},
+        {"慢请求使用info", LogConfig{SlowThreshold: time.Nanosecond, SlowLevel: &levelInfo}, "/ok", "info"},
+        {"慢请求使用error", LogConfig{SlowThreshold: time.Nanosecond, SlowLevel: &levelError}, "/ok", "error"},
+        {"状态码类别的级别更高", LogConfig{SlowThreshold: time.Nanosecond, SlowLevel: &levelInfo}, "/bad", "warn"},
+    }
+    for _, tt := range tests {
+        log := testLevelLogger{}
+        app := iris.New()
+        app.Use(LogMiddlewareWithConfig(log, tt.conf))
+        app.Get("/{any:path}", func(ctx iris.Context) {
+            if ctx.Path() == "/bad" {
+                ctx.StatusCode(400)
+            }
+        })
+        if err := app.Build(); err != nil {
+            t.Fatal(err)
+        }
+        app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", tt.path, nil))
+
+        if tt.expect == "" {
+            if len(log) != 0 {
+                t.Fatal(tt.name, "不应该记录日志", log)
+            }
+            continue
+        }
+        if len(log) != 1 || len(log[tt.expect]) != 1 {
+            t.Fatal(tt.name, "日志级别错误", log)
+        }
+    }
+}
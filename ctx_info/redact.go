@@ -0,0 +1,390 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  敏感数据脱敏
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "bytes"
+    "encoding/json"
+    "net/url"
+    "strings"
+)
+
+// 默认的脱敏替换文本
+const DefaultRedactMask = "******"
+
+// 脱敏配置
+type Redaction struct {
+    // 替换文本, 为空时使用 DefaultRedactMask
+    Mask string
+    // 需要脱敏的header名, 不区分大小写, 同时作用于请求header和响应header
+    Headers []string
+    // 需要脱敏的json字段, 不包含点的字段名(如 password)匹配任意层级的同名字段
+    // 包含点的字段路径(如 card.number)从根开始匹配, 遇到数组时会作用于每个元素
+    // 请求体被截断时使用相同的规则
+    BodyFields []string
+    // 需要脱敏的表单字段名, 作用于 application/x-www-form-urlencoded 的请求体
+    FormFields []string
+    // 需要脱敏的get参数名
+    QueryParams []string
+}
+
+// 默认脱敏配置
+func DefaultRedaction() *Redaction {
+    return &Redaction{
+        Mask:    DefaultRedactMask,
+        Headers: []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"},
+    }
+}
+
+var redaction = compileRedaction(DefaultRedaction())
+
+// 编译后的脱敏配置
+type compiledRedaction struct {
+    mask        string
+    headers     map[string]struct{}
+    // 匹配任意层级的字段名
+    bodyNames map[string]struct{}
+    // 从根开始匹配的字段路径
+    bodyPaths   [][]string
+    formFields  map[string]struct{}
+    queryParams map[string]struct{}
+}
+
+func compileRedaction(r *Redaction) *compiledRedaction {
+    c := &compiledRedaction{
+        mask:        r.Mask,
+        headers:     make(map[string]struct{}, len(r.Headers)),
+        bodyNames:   make(map[string]struct{}),
+        formFields:  make(map[string]struct{}, len(r.FormFields)),
+        queryParams: make(map[string]struct{}, len(r.QueryParams)),
+    }
+    if c.mask == "" {
+        c.mask = DefaultRedactMask
+    }
+    for _, h := range r.Headers {
+        c.headers[strings.ToLower(h)] = struct{}{}
+    }
+    for _, f := range r.FormFields {
+        c.formFields[f] = struct{}{}
+    }
+    for _, q := range r.QueryParams {
+        c.queryParams[q] = struct{}{}
+    }
+
+    for _, f := range r.BodyFields {
+        if !strings.Contains(f, ".") {
+            c.bodyNames[f] = struct{}{}
+            continue
+        }
+        c.bodyPaths = append(c.bodyPaths, strings.Split(f, "."))
+    }
+    return c
+}
+
+// 设置脱敏配置, 传入nil表示不脱敏
+func SetRedaction(r *Redaction) {
+    if r == nil {
+        r = &Redaction{}
+    }
+    redaction = compileRedaction(r)
+}
+
+// 检查header是否需要脱敏
+func redactHeaderName(name string) bool {
+    _, ok := redaction.headers[strings.ToLower(name)]
+    return ok
+}
+
+// 对get参数脱敏, 返回脱敏后的 RequestURI
+func RedactURI(u *url.URL) string {
+    uri := u.RequestURI()
+    if len(redaction.queryParams) == 0 || u.RawQuery == "" {
+        return uri
+    }
+    return uri[:len(uri)-len(u.RawQuery)] + redactQueryString(u.RawQuery, redaction.queryParams)
+}
+
+// 对 a=1&b=2 格式的数据脱敏, 保持原有的顺序和编码
+func redactQueryString(s string, names map[string]struct{}) string {
+    pairs := strings.Split(s, "&")
+    for i, pair := range pairs {
+        rawKey := pair
+        if k := strings.IndexByte(pair, '='); k != -1 {
+            rawKey = pair[:k]
+        }
+        key, err := url.QueryUnescape(rawKey)
+        if err != nil {
+            key = rawKey
+        }
+        if _, ok := names[key]; ok {
+            pairs[i] = rawKey + "=" + redaction.mask
+        }
+    }
+    return strings.Join(pairs, "&")
+}
+
+// 根据 Content-Type 对请求体或响应体脱敏
+// 其它 Content-Type (如 text/plain) 的数据如果看起来是json也会按json脱敏
+func RedactBody(contentType string, body []byte) []byte {
+    if len(body) == 0 {
+        return body
+    }
+    contentType = strings.ToLower(contentType)
+    if strings.Contains(contentType, "x-www-form-urlencoded") {
+        return redactForm(body)
+    }
+    if strings.Contains(contentType, "json") || contentType == "" || looksLikeJson(body) {
+        return redactJson(body)
+    }
+    return body
+}
+
+// 检查数据是否看起来是json对象或数组
+func looksLikeJson(body []byte) bool {
+    body = bytes.TrimSpace(body)
+    return len(body) > 0 && (body[0] == '{' || body[0] == '[')
+}
+
+// 对表单脱敏
+func redactForm(body []byte) []byte {
+    if len(redaction.formFields) == 0 {
+        return body
+    }
+    return []byte(redactQueryString(string(body), redaction.formFields))
+}
+
+// 对json脱敏, 如果json不完整会扫描字段并替换匹配的值, 规则见 Redaction.BodyFields
+func redactJson(body []byte) []byte {
+    if len(redaction.bodyNames) == 0 && len(redaction.bodyPaths) == 0 {
+        return body
+    }
+
+    var data interface{}
+    decoder := json.NewDecoder(bytes.NewReader(body))
+    decoder.UseNumber()
+    if err := decoder.Decode(&data); err != nil || decoder.More() {
+        return redactBrokenJson(body)
+    }
+
+    changed := redactJsonNames(data)
+    for _, path := range redaction.bodyPaths {
+        if redactJsonPath(data, path) {
+            changed = true
+        }
+    }
+    if !changed {
+        return body
+    }
+    out, err := json.Marshal(data)
+    if err != nil {
+        return body
+    }
+    return out
+}
+
+// 对任意层级的字段名脱敏, 返回是否有数据被修改
+func redactJsonNames(data interface{}) bool {
+    if len(redaction.bodyNames) == 0 {
+        return false
+    }
+    changed := false
+    switch v := data.(type) {
+    case map[string]interface{}:
+        for k, child := range v {
+            if _, ok := redaction.bodyNames[k]; ok {
+                v[k] = redaction.mask
+                changed = true
+            } else if redactJsonNames(child) {
+                changed = true
+            }
+        }
+    case []interface{}:
+        for _, child := range v {
+            if redactJsonNames(child) {
+                changed = true
+            }
+        }
+    }
+    return changed
+}
+
+// 按照从根开始的路径对json数据脱敏, 返回是否有数据被修改
+func redactJsonPath(data interface{}, path []string) bool {
+    switch v := data.(type) {
+    case map[string]interface{}:
+        child, ok := v[path[0]]
+        if !ok {
+            return false
+        }
+        if len(path) == 1 {
+            v[path[0]] = redaction.mask
+            return true
+        }
+        return redactJsonPath(child, path[1:])
+    case []interface{}:
+        changed := false
+        for _, child := range v {
+            if redactJsonPath(child, path) {
+                changed = true
+            }
+        }
+        return changed
+    }
+    return false
+}
+
+// 检查字段路径是否需要脱敏, keys为从根开始的字段名, 不包含数组
+func matchRedactKeys(keys []string) bool {
+    if _, ok := redaction.bodyNames[keys[len(keys)-1]]; ok {
+        return true
+    }
+    for _, path := range redaction.bodyPaths {
+        if len(path) != len(keys) {
+            continue
+        }
+        match := true
+        for i := range path {
+            if path[i] != keys[i] {
+                match = false
+                break
+            }
+        }
+        if match {
+            return true
+        }
+    }
+    return false
+}
+
+// 扫描不完整json时的容器
+type jsonScanFrame struct {
+    object bool
+    // 对象中当前的字段名
+    key string
+    // 对象中下一个字符串是否为字段名
+    expectKey bool
+}
+
+// 对不完整的json脱敏, 按照字段路径匹配, 字段的值可以是字符串, 数字, 数组或对象, 值被截断时替换到末尾
+func redactBrokenJson(body []byte) []byte {
+    var buff bytes.Buffer
+    var stack []*jsonScanFrame
+    last := 0
+    for i := 0; i < len(body); {
+        var top *jsonScanFrame
+        if len(stack) > 0 {
+            top = stack[len(stack)-1]
+        }
+        switch body[i] {
+        case '{', '[':
+            stack = append(stack, &jsonScanFrame{object: body[i] == '{', expectKey: body[i] == '{'})
+            i++
+        case '}', ']':
+            if len(stack) > 0 {
+                stack = stack[:len(stack)-1]
+            }
+            i++
+        case ',':
+            if top != nil && top.object {
+                top.expectKey = true
+            }
+            i++
+        case '"':
+            end := skipJsonString(body, i)
+            if top != nil && top.object && top.expectKey {
+                top.key = unquoteJsonKey(body[i:end])
+                top.expectKey = false
+            }
+            i = end
+        case ':':
+            i++
+            if top == nil || !top.object {
+                continue
+            }
+            keys := make([]string, 0, len(stack))
+            for _, f := range stack {
+                if f.object {
+                    keys = append(keys, f.key)
+                }
+            }
+            if !matchRedactKeys(keys) {
+                continue
+            }
+            for i < len(body) && strings.IndexByte(" \t\r\n", body[i]) != -1 {
+                i++
+            }
+            buff.Write(body[last:i])
+            buff.WriteString(`"` + redaction.mask + `"`)
+            i = skipJsonValue(body, i)
+            last = i
+        default:
+            i++
+        }
+    }
+    if last == 0 {
+        return body
+    }
+    buff.Write(body[last:])
+    return buff.Bytes()
+}
+
+// 解析json字段名, 字段名不完整时返回去掉引号的原文
+func unquoteJsonKey(b []byte) string {
+    var key string
+    if err := json.Unmarshal(b, &key); err == nil {
+        return key
+    }
+    return strings.Trim(string(b), `"`)
+}
+
+// 跳过从i开始的json值, 返回值结束后的位置, 值不完整时返回数据长度
+func skipJsonValue(b []byte, i int) int {
+    if i >= len(b) {
+        return len(b)
+    }
+    switch b[i] {
+    case '"':
+        return skipJsonString(b, i)
+    case '[', '{':
+        depth := 0
+        for i < len(b) {
+            switch b[i] {
+            case '"':
+                i = skipJsonString(b, i)
+                continue
+            case '[', '{':
+                depth++
+            case ']', '}':
+                depth--
+                if depth == 0 {
+                    return i + 1
+                }
+            }
+            i++
+        }
+        return len(b)
+    }
+    for i < len(b) && !strings.ContainsRune(",]} \t\r\n", rune(b[i])) {
+        i++
+    }
+    return i
+}
+
+// 跳过从i开始的json字符串, 返回字符串结束后的位置, 字符串不完整时返回数据长度
+func skipJsonString(b []byte, i int) int {
+    for i++; i < len(b); i++ {
+        switch b[i] {
+        case '\\':
+            i++
+        case '"':
+            return i + 1
+        }
+    }
+    return len(b)
+}
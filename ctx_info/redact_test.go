@@ -0,0 +1,172 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "net/http"
+    "net/url"
+    "strings"
+    "testing"
+
+    "github.com/kataras/iris/v12"
+)
+
+// 测试使用的脱敏配置
+func testRedaction() *Redaction {
+    return &Redaction{
+        Mask:        "***",
+        Headers:     []string{"Authorization", "x-token"},
+        BodyFields:  []string{"password", "card.number"},
+        FormFields:  []string{"password"},
+        QueryParams: []string{"token"},
+    }
+}
+
+func TestRedactHeader(t *testing.T) {
+    defer SetRedaction(DefaultRedaction())
+    SetRedaction(testRedaction())
+
+    header := http.Header{}
+    header.Set("Authorization", "Bearer abc")
+    header.Set("X-Token", "abc")
+    header.Set("X-Name", "zhang")
+
+    tests := []struct {
+        name   string
+        expect string
+    }{
+        {"Authorization", "***"},
+        {"authorization", "***"},
+        {"X-Token", "***"},
+        {"X-Name", "zhang"},
+        {"X-Not-Exists", ""},
+    }
+    for _, tt := range tests {
+        if s := getHeaderValue(header, tt.name); s != tt.expect {
+            t.Fatal(tt.name, "输出错误", s)
+        }
+    }
+
+    hm := makeHeaderMap(header)
+    if hm["Authorization"] != "***" || hm["X-Token"] != "***" || hm["X-Name"] != "zhang" {
+        t.Fatal("输出错误", hm)
+    }
+}
+
+func TestRedactBody(t *testing.T) {
+    defer SetRedaction(DefaultRedaction())
+    SetRedaction(testRedaction())
+
+    tests := []struct {
+        name        string
+        contentType string
+        body        string
+        expect      string
+    }{
+        {"json", "application/json", `{"password":"123","a":1}`, `{"a":1,"password":"***"}`},
+        {"嵌套字段", "application/json", `{"card":{"number":"6222","cvv":"1"}}`, `{"card":{"cvv":"1","number":"***"}}`},
+        {"数组中的字段", "application/json", `[{"password":"1"},{"password":"2"},{"a":3}]`, `[{"password":"***"},{"password":"***"},{"a":3}]`},
+        {"数组的嵌套字段", "application/json", `{"card":[{"number":1},{"number":2}]}`, `{"card":[{"number":"***"},{"number":"***"}]}`},
+        {"值为数组或对象", "application/json", `{"password":["a",{"b":"c"}]}`, `{"password":"***"}`},
+        {"不完整路径不脱敏", "application/json", `{"number":"6222"}`, `{"number":"6222"}`},
+        {"没有 Content-Type", "", `{"password":"123"}`, `{"password":"***"}`},
+        {"text/plain 的json", "text/plain", `{"password":"123"}`, `{"password":"***"}`},
+        {"text/plain 的文本", "text/plain", `password=123`, `password=123`},
+        {"截断的字符串", "application/json", `{"a":1,"password":"12`, `{"a":1,"password":"***"`},
+        {"截断的数字", "application/json", `{"password":123,"a":`, `{"password":"***","a":`},
+        {"截断的数组", "application/json", `{"password":["hunter2"],"a":"b`, `{"password":"***","a":"b`},
+        {"截断的数组内部", "application/json", `{"password":["hunter2",`, `{"password":"***"`},
+        {"截断的对象", "application/json", `{"password":{"v":"]}\"x"},"card":{"number":{"a":[1,2]}},"c":`, `{"password":"***","card":{"number":"***"},"c":`},
+        {"任意层级的字段名", "application/json", `{"user":{"password":"x"}}`, `{"user":{"password":"***"}}`},
+        {"截断的任意层级的字段名", "application/json", `{"user":{"password":"x"},"a":"b`, `{"user":{"password":"***"},"a":"b`},
+        {"数组中任意层级的字段名", "application/json", `{"users":[{"info":{"password":"x"}}]}`, `{"users":[{"info":{"password":"***"}}]}`},
+        {"截断的数组中任意层级的字段名", "application/json", `{"users":[{"info":{"password":"x"}},{"info":{"pass`, `{"users":[{"info":{"password":"***"}},{"info":{"pass`},
+        {"字段路径从根开始匹配", "application/json", `{"x":{"card":{"number":"6222"}}}`, `{"x":{"card":{"number":"6222"}}}`},
+        {"截断的字段路径从根开始匹配", "application/json", `{"x":{"card":{"number":"6222"}},"a":"b`, `{"x":{"card":{"number":"6222"}},"a":"b`},
+        {"截断的数组的嵌套字段", "application/json", `{"card":[{"number":1},{"number":2`, `{"card":[{"number":"***"},{"number":"***"`},
+        {"截断的字符串中的字段名", "application/json", `{"a":"\"password\":1","b`, `{"a":"\"password\":1","b`},
+        {"截断的转义字符串", "application/json", `{"password":"a\"b","c`, `{"password":"***","c`},
+        {"表单", "application/x-www-form-urlencoded", `a=1&password=123&b=2`, `a=1&password=***&b=2`},
+        {"表单编码的字段名", "application/x-www-form-urlencoded", `pass%77ord=123`, `pass%77ord=***`},
+    }
+    for _, tt := range tests {
+        if s := string(RedactBody(tt.contentType, []byte(tt.body))); s != tt.expect {
+            t.Fatal(tt.name, "输出错误", s)
+        }
+    }
+}
+
+func TestRedactBodyTruncated(t *testing.T) {
+    defer SetRedaction(DefaultRedaction())
+    SetRedaction(testRedaction())
+
+    // 在任意位置截断时都不能泄漏需要脱敏的值
+    bodies := []string{
+        `{"password":"hunter2","a":1}`,
+        `{"user":{"name":"a","password":"hunter2"},"b":[1,2]}`,
+        `{"list":[{"password":["hunter2",{"x":"hunter2"}]}],"c":"d"}`,
+        `{"card":{"number":"hunter2","cvv":"1"},"e":{"f":1}}`,
+        `[{"card":[{"number":"hunter2"}]},{"password":{"v":"hunter2"}}]`,
+    }
+    for _, body := range bodies {
+        for n := 1; n <= len(body); n++ {
+            out := string(RedactBody("application/json", []byte(body[:n])))
+            if strings.Contains(out, "hun") {
+                t.Fatal("截断后泄漏了需要脱敏的值", body[:n], out)
+            }
+        }
+    }
+}
+
+func TestRedactURI(t *testing.T) {
+    defer SetRedaction(DefaultRedaction())
+    SetRedaction(testRedaction())
+
+    tests := []struct {
+        uri    string
+        expect string
+    }{
+        {"/a", "/a"},
+        {"/a?b=1", "/a?b=1"},
+        {"/a?token=abc&b=1", "/a?token=***&b=1"},
+        {"/a?b=1&tok%65n=abc&token", "/a?b=1&tok%65n=***&token=***"},
+        {"/a%20b?token=abc", "/a%20b?token=***"},
+    }
+    for _, tt := range tests {
+        u, err := url.Parse(tt.uri)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if s := RedactURI(u); s != tt.expect {
+            t.Fatal(tt.uri, "输出错误", s)
+        }
+    }
+}
+
+func TestRedactLayout(t *testing.T) {
+    defer SetRedaction(DefaultRedaction())
+    SetRedaction(testRedaction())
+
+    testRequest(t, "GET", "/a?token=abc&b=1", func(ctx iris.Context) {}, func(ctx iris.Context) {
+        tests := []struct {
+            layout string
+            expect string
+        }{
+            {"%(fullpath)s", "/a?token=***&b=1"},
+            {"%(query)s", "token=***&b=1"},
+            {"%(query:token)s", "***"},
+            {"%(query:b)s", "1"},
+        }
+        for _, tt := range tests {
+            if s := GetInfoOfLayout(ctx, tt.layout); s != tt.expect {
+                t.Fatal(tt.layout, "输出错误", s)
+            }
+        }
+    })
+}
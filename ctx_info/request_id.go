@@ -0,0 +1,96 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  请求id
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "crypto/rand"
+    "encoding/hex"
+    "strconv"
+    "sync/atomic"
+    "time"
+
+    "github.com/kataras/iris/v12"
+)
+
+// 请求id标记
+const RequestIDField = "ctx_request_id"
+
+// 读取和回应请求id的header
+const RequestIDHeader = "X-Request-ID"
+
+// 请求id的最大长度, 客户端传入的请求id超过这个长度会被忽略
+const MaxRequestIDLength = 128
+
+var requestIDGenerator = NewRequestID
+
+var requestIDSeq uint64
+
+// 生成一个随机的请求id
+func NewRequestID() string {
+    var b [16]byte
+    if _, err := rand.Read(b[:]); err != nil {
+        return strconv.FormatInt(time.Now().UnixNano(), 16) + strconv.FormatUint(atomic.AddUint64(&requestIDSeq, 1), 16)
+    }
+    return hex.EncodeToString(b[:])
+}
+
+// 设置请求id生成器
+func SetRequestIDGenerator(fn func() string) {
+    if fn == nil {
+        fn = NewRequestID
+    }
+    requestIDGenerator = fn
+}
+
+// 设置请求id, 将请求id放入 ctx.Values() 的 RequestIDField 字段中
+func SetRequestID(ctx iris.Context, id string) {
+    ctx.Values().Set(RequestIDField, id)
+}
+
+// 获取请求id, 如果没有设置则返回空字符串
+func GetRequestID(ctx iris.Context) string {
+    return ctx.Values().GetString(RequestIDField)
+}
+
+// 获取请求id, 如果没有设置则从请求头 RequestIDHeader 读取, 如果请求头中没有则生成一个新的
+// 请求id会被放入 ctx.Values() 中并设置到响应头
+func EnsureRequestID(ctx iris.Context) string {
+    if id := GetRequestID(ctx); id != "" {
+        return id
+    }
+
+    id := ctx.GetHeader(RequestIDHeader)
+    if !isValidRequestID(id) {
+        id = requestIDGenerator()
+    }
+    SetRequestID(ctx, id)
+    ctx.Header(RequestIDHeader, id)
+    return id
+}
+
+// 检查客户端传入的请求id
+func isValidRequestID(id string) bool {
+    if id == "" || len(id) > MaxRequestIDLength {
+        return false
+    }
+    for i := 0; i < len(id); i++ {
+        if id[i] < 0x21 || id[i] > 0x7e {
+            return false
+        }
+    }
+    return true
+}
+
+// 请求id中间件, 读取或生成请求id, 并回应到响应头中
+func RequestIDMiddleware() func(ctx iris.Context) {
+    return func(ctx iris.Context) {
+        EnsureRequestID(ctx)
+        ctx.Next()
+    }
+}
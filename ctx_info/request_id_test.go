@@ -0,0 +1,66 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/kataras/iris/v12"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+    app := iris.New()
+    app.Use(RequestIDMiddleware())
+    app.Get("/", func(ctx iris.Context) {
+        _, _ = ctx.WriteString(GetRequestID(ctx) + "|" + GetInfoOfLayout(ctx, "%(request_id)s"))
+    })
+    if err := app.Build(); err != nil {
+        t.Fatal(err)
+    }
+
+    SetRequestIDGenerator(func() string { return "generated" })
+    defer SetRequestIDGenerator(nil)
+
+    tests := []struct {
+        name   string
+        header string
+        expect string
+    }{
+        {"没有请求id", "", "generated"},
+        {"有效的请求id", "abc-123", "abc-123"},
+        {"包含空格", "abc 123", "generated"},
+        {"包含控制字符", "abc\x01", "generated"},
+        {"超过最大长度", strings.Repeat("a", MaxRequestIDLength+1), "generated"},
+        {"等于最大长度", strings.Repeat("a", MaxRequestIDLength), strings.Repeat("a", MaxRequestIDLength)},
+    }
+    for _, tt := range tests {
+        req := httptest.NewRequest("GET", "/", nil)
+        if tt.header != "" {
+            req.Header.Set(RequestIDHeader, tt.header)
+        }
+        w := httptest.NewRecorder()
+        app.ServeHTTP(w, req)
+
+        if w.Body.String() != tt.expect+"|"+tt.expect {
+            t.Fatal(tt.name, "请求id错误", w.Body.String())
+        }
+        if w.Header().Get(RequestIDHeader) != tt.expect {
+            t.Fatal(tt.name, "响应头错误", w.Header().Get(RequestIDHeader))
+        }
+    }
+}
+
+func TestNewRequestID(t *testing.T) {
+    a, b := NewRequestID(), NewRequestID()
+    if len(a) != 32 || a == b || !isValidRequestID(a) {
+        t.Fatal("生成的请求id错误", a, b)
+    }
+}
@@ -0,0 +1,101 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  响应信息
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "net/http"
+    "strconv"
+    "strings"
+
+    "github.com/kataras/iris/v12"
+    "github.com/zlyuancn/zmap"
+)
+
+// 默认输出的响应体最大字节数
+const DefaultRespBodyLimit = 4096
+
+var respBodyLimit = DefaultRespBodyLimit
+
+// 允许输出响应体的 Content-Type, 包含其中任意一个即可
+var respBodyContentTypes = []string{"text/", "json", "xml", "javascript", "x-www-form-urlencoded"}
+
+// 设置输出的响应体最大字节数, 超出的部分会被截断, 如果n<=0则不限制
+func SetRespBodyLimit(n int) {
+    respBodyLimit = n
+}
+
+// 设置允许输出响应体的 Content-Type, 响应的 Content-Type 包含其中任意一个即可, 其它类型的响应体不会输出
+func SetRespBodyContentTypes(types ...string) {
+    respBodyContentTypes = append(([]string)(nil), types...)
+}
+
+// 开始记录响应, 必须在写入响应之前调用, 否则无法获取响应体
+// 注意, 记录响应时响应体会缓存在内存中直到请求处理完毕
+func RecordResponse(ctx iris.Context) {
+    ctx.Record()
+}
+
+// 记录响应中间件, 使用 RespBodyFlag 时需要在处理请求之前记录响应
+func RecordResponseMiddleware() func(ctx iris.Context) {
+    return func(ctx iris.Context) {
+        RecordResponse(ctx)
+        ctx.Next()
+    }
+}
+
+// 获取响应大小
+func getRespSize(ctx iris.Context) int {
+    if rec, ok := ctx.IsRecording(); ok {
+        return len(rec.Body())
+    }
+    if n := ctx.ResponseWriter().Written(); n > 0 {
+        return n
+    }
+    return 0
+}
+
+// 获取经过脱敏和过滤的响应header
+func getRespHeader(ctx iris.Context) zmap.M {
+    return makeHeaderMap(ctx.ResponseWriter().Header())
+}
+
+// 获取响应体, 没有记录响应或者 Content-Type 不被允许时返回空字符串
+func getRespBody(ctx iris.Context) string {
+    rec, ok := ctx.IsRecording()
+    if !ok {
+        return ""
+    }
+    body := rec.Body()
+    if len(body) == 0 {
+        return ""
+    }
+
+    contentType := rec.Header().Get("Content-Type")
+    if contentType == "" {
+        contentType = http.DetectContentType(body)
+    }
+    if !isAllowContentType(contentType, respBodyContentTypes) {
+        return "(" + contentType + ", " + strconv.Itoa(len(body)) + " bytes)"
+    }
+    if respBodyLimit > 0 && len(body) > respBodyLimit {
+        return string(RedactBody(contentType, body[:respBodyLimit])) + "...(truncated, total " + strconv.Itoa(len(body)) + " bytes)"
+    }
+    return string(RedactBody(contentType, body))
+}
+
+// 检查 Content-Type 是否包含其中任意一个
+func isAllowContentType(contentType string, types []string) bool {
+    contentType = strings.ToLower(contentType)
+    for _, t := range types {
+        if strings.Contains(contentType, t) {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,87 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/kataras/iris/v12"
+)
+
+func TestRespFlags(t *testing.T) {
+    defer SetRespBodyLimit(DefaultRespBodyLimit)
+    SetRespBodyLimit(10)
+
+    const layout = "%(resp_size)s|%(resp_header:Content-Type)s|%(resp_body)s"
+    tests := []struct {
+        name    string
+        record  bool
+        handler iris.Handler
+        expect  string
+    }{
+        {"json", true, func(ctx iris.Context) {
+            _, _ = ctx.JSON(iris.Map{"a": 1})
+        }, `7|application/json; charset=UTF-8|{"a":1}`},
+        {"超过长度限制", true, func(ctx iris.Context) {
+            ctx.ContentType("text/plain")
+            _, _ = ctx.WriteString("0123456789abcdef")
+        }, "16|text/plain; charset=UTF-8|0123456789...(truncated, total 16 bytes)"},
+        {"二进制响应", true, func(ctx iris.Context) {
+            ctx.Header("Content-Type", "image/png")
+            _, _ = ctx.Write([]byte{0x89, 'P', 'N', 'G', 0, 1})
+        }, "6|image/png|(image/png, 6 bytes)"},
+        {"没有 Content-Type 的二进制响应", true, func(ctx iris.Context) {
+            _, _ = ctx.Write([]byte{0, 1, 2, 3})
+        }, "4||(application/octet-stream, 4 bytes)"},
+        {"没有记录响应", false, func(ctx iris.Context) {
+            ctx.ContentType("text/plain")
+            _, _ = ctx.WriteString("abc")
+        }, "3|text/plain; charset=UTF-8|"},
+    }
+    for _, tt := range tests {
+        var got string
+        app := iris.New()
+        app.Use(func(ctx iris.Context) {
+            if tt.record {
+                RecordResponse(ctx)
+            }
+            ctx.Next()
+            got = GetInfoOfLayout(ctx, layout)
+        })
+        app.Get("/", tt.handler)
+        if err := app.Build(); err != nil {
+            t.Fatal(err)
+        }
+        w := httptest.NewRecorder()
+        app.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+        if got != tt.expect {
+            t.Fatal(tt.name, "输出错误", got)
+        }
+        if w.Body.Len() == 0 {
+            t.Fatal(tt.name, "记录响应后应该正常输出响应体")
+        }
+    }
+}
+
+func TestRespBodyRedact(t *testing.T) {
+    defer SetRedaction(DefaultRedaction())
+    SetRedaction(&Redaction{BodyFields: []string{"token"}})
+
+    testRequest(t, "GET", "/", func(ctx iris.Context) {
+        RecordResponse(ctx)
+        _, _ = ctx.JSON(iris.Map{"token": "secret", "a": 1})
+    }, func(ctx iris.Context) {
+        if s := GetInfoOfLayout(ctx, "%(resp_body)s"); strings.Contains(s, "secret") {
+            t.Fatal("响应体应该被脱敏", s)
+        }
+    })
+}
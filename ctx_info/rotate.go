@@ -0,0 +1,125 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  滚动日志文件
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "fmt"
+    "os"
+    "path/filepath"
+    "sync"
+)
+
+// 默认滚动文件的最大字节数
+const DefaultRotateMaxSize = 100 << 20
+
+// 默认保留的备份文件数量
+const DefaultRotateBackups = 7
+
+// 按大小滚动的日志文件, 可以直接传给 JsonLogMiddleware 或 log.New
+// 文件大小超过最大字节数时, 会将 name 重命名为 name.1, name.1 重命名为 name.2, 依此类推, 超过备份数量的文件会被删除
+type RotateFile struct {
+    name       string
+    maxSize    int64
+    maxBackups int
+
+    mx   sync.Mutex
+    file *os.File
+    size int64
+}
+
+// 创建滚动日志文件, maxSize为0时使用 DefaultRotateMaxSize, maxBackups为0时使用 DefaultRotateBackups
+func NewRotateFile(name string, maxSize int64, maxBackups int) (*RotateFile, error) {
+    if maxSize <= 0 {
+        maxSize = DefaultRotateMaxSize
+    }
+    if maxBackups <= 0 {
+        maxBackups = DefaultRotateBackups
+    }
+    m := &RotateFile{name: name, maxSize: maxSize, maxBackups: maxBackups}
+    if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+        return nil, err
+    }
+    if err := m.open(); err != nil {
+        return nil, err
+    }
+    return m, nil
+}
+
+// 打开文件
+func (m *RotateFile) open() error {
+    f, err := os.OpenFile(m.name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+    if err != nil {
+        return err
+    }
+    info, err := f.Stat()
+    if err != nil {
+        _ = f.Close()
+        return err
+    }
+    m.file, m.size = f, info.Size()
+    return nil
+}
+
+// 滚动文件, 重命名失败时会重新打开原文件, 只有重新打开也失败时 m.file 才会为nil
+func (m *RotateFile) rotate() error {
+    if err := m.file.Close(); err != nil {
+        m.file = nil
+        if openErr := m.open(); openErr != nil {
+            return openErr
+        }
+        return err
+    }
+    m.file = nil
+
+    _ = os.Remove(fmt.Sprintf("%s.%d", m.name, m.maxBackups))
+    for i := m.maxBackups - 1; i >= 1; i-- {
+        _ = os.Rename(fmt.Sprintf("%s.%d", m.name, i), fmt.Sprintf("%s.%d", m.name, i+1))
+    }
+    renameErr := os.Rename(m.name, m.name+".1")
+    if err := m.open(); err != nil {
+        return err
+    }
+    return renameErr
+}
+
+// 写入数据, 滚动失败时仍然会写入原文件并返回滚动的错误, 下次写入时会再次尝试滚动
+func (m *RotateFile) Write(p []byte) (int, error) {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+
+    if m.file == nil {
+        return 0, os.ErrClosed
+    }
+    var rotateErr error
+    if m.size > 0 && m.size+int64(len(p)) > m.maxSize {
+        rotateErr = m.rotate()
+        if m.file == nil {
+            return 0, rotateErr
+        }
+    }
+    n, err := m.file.Write(p)
+    m.size += int64(n)
+    if err == nil {
+        err = rotateErr
+    }
+    return n, err
+}
+
+// 关闭文件
+func (m *RotateFile) Close() error {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+
+    if m.file == nil {
+        return nil
+    }
+    err := m.file.Close()
+    m.file = nil
+    return err
+}
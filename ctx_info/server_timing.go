@@ -0,0 +1,57 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  Server-Timing
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/kataras/iris/v12"
+)
+
+// Server-Timing header
+const ServerTimingHeader = "Server-Timing"
+
+// 总耗时的阶段名
+const ServerTimingTotal = "total"
+
+// 生成 Server-Timing 的值, 如 db;dur=12.345, total;dur=20.001
+// 阶段名应该是 token, 如 db, cache, render, 不能包含空格, 逗号和分号等字符
+func makeServerTiming(timings []Timing, total time.Duration) string {
+    parts := make([]string, 0, len(timings)+1)
+    for _, t := range timings {
+        parts = append(parts, t.Name+";dur="+formatServerTimingDur(t.Duration))
+    }
+    if total >= 0 {
+        parts = append(parts, ServerTimingTotal+";dur="+formatServerTimingDur(total))
+    }
+    return strings.Join(parts, ", ")
+}
+
+// 以毫秒格式化耗时
+func formatServerTimingDur(d time.Duration) string {
+    return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', 3, 64)
+}
+
+// Server-Timing 中间件, 在响应中输出 Server-Timing header, 包含 Mark, AddTiming, StartTiming 记录的阶段耗时和总耗时
+// 为了在处理完请求之后设置header, 中间件会记录响应(ctx.Record), 所以不适用于流式响应, 应该注册在其它中间件之前
+// 注意, 浏览器开发者工具中可以看到这些耗时, 生产环境中可能需要只对内部请求启用
+func ServerTimingMiddleware() func(ctx iris.Context) {
+    return func(ctx iris.Context) {
+        SetStartTime(ctx)
+        ctx.Record()
+        ctx.Next()
+
+        if _, ok := ctx.IsRecording(); !ok {
+            return
+        }
+        ctx.ResponseWriter().Header().Set(ServerTimingHeader, makeServerTiming(GetTimings(ctx), GetLatency(ctx)))
+    }
+}
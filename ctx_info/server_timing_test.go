@@ -0,0 +1,43 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/kataras/iris/v12"
+)
+
+func TestServerTimingMiddleware(t *testing.T) {
+    app := iris.New()
+    app.Use(ServerTimingMiddleware())
+    app.Get("/", func(ctx iris.Context) {
+        AddTiming(ctx, "db", 12*time.Millisecond)
+        stop := StartTiming(ctx, "cache")
+        stop()
+        Mark(ctx, MarkHandler)
+        _, _ = ctx.WriteString("ok")
+    })
+    if err := app.Build(); err != nil {
+        t.Fatal(err)
+    }
+    w := httptest.NewRecorder()
+    app.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+    h := w.Header().Get(ServerTimingHeader)
+    if !strings.HasPrefix(h, "db;dur=12.000, cache;dur=") || !strings.Contains(h, ", handler;dur=") || !strings.Contains(h, ", total;dur=") {
+        t.Fatal("Server-Timing错误", h)
+    }
+    if w.Body.String() != "ok" {
+        t.Fatal("响应错误", w.Body.String())
+    }
+}
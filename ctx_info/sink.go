@@ -0,0 +1,112 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  日志适配器
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "fmt"
+    "log"
+    "os"
+)
+
+// 注意, 以下日志库可以直接使用, 不需要适配:
+//   *zap.SugaredLogger 实现了 Logger 和 StructuredLogger
+//   *logrus.Logger 和 *logrus.Entry 实现了 Logger
+
+// 标准库日志适配器
+type stdLogger struct {
+    l *log.Logger
+}
+
+// 使用标准库的 *log.Logger 创建日志记录器, 输出时会加上级别前缀, 如 [WARN]
+// 如果l为nil, 输出到 os.Stderr
+func NewStdLogger(l *log.Logger) Logger {
+    if l == nil {
+        l = log.New(os.Stderr, "", log.LstdFlags)
+    }
+    return stdLogger{l}
+}
+
+func (m stdLogger) Info(v ...interface{})  { m.l.Print("[INFO] " + fmt.Sprint(v...)) }
+func (m stdLogger) Warn(v ...interface{})  { m.l.Print("[WARN] " + fmt.Sprint(v...)) }
+func (m stdLogger) Error(v ...interface{}) { m.l.Print("[ERROR] " + fmt.Sprint(v...)) }
+
+// slog风格的日志记录器, *slog.Logger 实现了它
+type SlogLogger interface {
+    Info(msg string, args ...interface{})
+    Warn(msg string, args ...interface{})
+    Error(msg string, args ...interface{})
+}
+
+// slog风格日志适配器
+type slogLogger struct {
+    l SlogLogger
+}
+
+// 使用slog风格的日志记录器创建日志记录器, 它同时实现了 Logger 和 StructuredLogger
+// 结构化输出时键值对会作为 args 传入, 如 l.Info("access", "status", 200, ...)
+func NewSlogLogger(l SlogLogger) interface {
+    Logger
+    LeveledStructuredLogger
+} {
+    return slogLogger{l}
+}
+
+func (m slogLogger) Info(v ...interface{})  { m.l.Info(fmt.Sprint(v...)) }
+func (m slogLogger) Warn(v ...interface{})  { m.l.Warn(fmt.Sprint(v...)) }
+func (m slogLogger) Error(v ...interface{}) { m.l.Error(fmt.Sprint(v...)) }
+
+func (m slogLogger) Infow(msg string, keysAndValues ...interface{})  { m.l.Info(msg, keysAndValues...) }
+func (m slogLogger) Warnw(msg string, keysAndValues ...interface{})  { m.l.Warn(msg, keysAndValues...) }
+func (m slogLogger) Errorw(msg string, keysAndValues ...interface{}) { m.l.Error(msg, keysAndValues...) }
+
+// 格式化风格的日志记录器, zap的 *SugaredLogger 和 logrus 都实现了它
+type LogfLogger interface {
+    Infof(format string, args ...interface{})
+    Warnf(format string, args ...interface{})
+    Errorf(format string, args ...interface{})
+}
+
+// 格式化风格日志适配器
+type logfLogger struct {
+    l LogfLogger
+}
+
+// 使用格式化风格的日志记录器创建日志记录器
+func NewLogfLogger(l LogfLogger) Logger {
+    return logfLogger{l}
+}
+
+func (m logfLogger) Info(v ...interface{})  { m.l.Infof("%s", fmt.Sprint(v...)) }
+func (m logfLogger) Warn(v ...interface{})  { m.l.Warnf("%s", fmt.Sprint(v...)) }
+func (m logfLogger) Error(v ...interface{}) { m.l.Errorf("%s", fmt.Sprint(v...)) }
+
+// 字段日志函数, 用于适配以map接收字段的日志库, 如 logrus:
+//   ctx_info.FieldsLoggerFunc(func(level ctx_info.LogLevel, msg string, fields ctx_info.Fields) {
+//       logrus.WithFields(logrus.Fields(fields)).Info(msg)
+//   })
+type FieldsLoggerFunc func(level LogLevel, msg string, fields Fields)
+
+func (fn FieldsLoggerFunc) Infow(msg string, keysAndValues ...interface{}) {
+    fn(LevelInfo, msg, makeFields(keysAndValues))
+}
+func (fn FieldsLoggerFunc) Warnw(msg string, keysAndValues ...interface{}) {
+    fn(LevelWarn, msg, makeFields(keysAndValues))
+}
+func (fn FieldsLoggerFunc) Errorw(msg string, keysAndValues ...interface{}) {
+    fn(LevelError, msg, makeFields(keysAndValues))
+}
+
+// 将键值对转为字段
+func makeFields(keysAndValues []interface{}) Fields {
+    fields := make(Fields, len(keysAndValues)/2)
+    for i := 0; i+1 < len(keysAndValues); i += 2 {
+        fields[fmt.Sprint(keysAndValues[i])] = keysAndValues[i+1]
+    }
+    return fields
+}
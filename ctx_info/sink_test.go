@@ -0,0 +1,102 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "bytes"
+    "io/ioutil"
+    "log"
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+func TestNewStdLogger(t *testing.T) {
+    var buff bytes.Buffer
+    l := NewStdLogger(log.New(&buff, "", 0))
+    l.Warn("a", "b")
+    if buff.String() != "[WARN] ab\n" {
+        t.Fatal("输出错误", buff.String())
+    }
+}
+
+func TestRotateFile(t *testing.T) {
+    dir, err := ioutil.TempDir("", "ziris")
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer os.RemoveAll(dir)
+
+    name := filepath.Join(dir, "access.log")
+    f, err := NewRotateFile(name, 10, 2)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer f.Close()
+
+    for _, s := range []string{"aaaaaa\n", "bbbbbb\n", "cccccc\n", "dddddd\n"} {
+        if _, err := f.Write([]byte(s)); err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    for name, expect := range map[string]string{name: "dddddd\n", name + ".1": "cccccc\n", name + ".2": "bbbbbb\n"} {
+        bs, err := ioutil.ReadFile(name)
+        if err != nil || string(bs) != expect {
+            t.Fatal("滚动错误", name, string(bs), err)
+        }
+    }
+    if _, err := os.Stat(name + ".3"); !os.IsNotExist(err) {
+        t.Fatal("超过备份数量的文件应该被删除")
+    }
+}
+
+func TestRotateFileRenameFailed(t *testing.T) {
+    dir, err := ioutil.TempDir("", "ziris")
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer os.RemoveAll(dir)
+
+    // 备份文件的位置是一个非空目录, 重命名会失败
+    name := filepath.Join(dir, "access.log")
+    if err := os.MkdirAll(filepath.Join(name+".1", "x"), 0755); err != nil {
+        t.Fatal(err)
+    }
+    f, err := NewRotateFile(name, 10, 1)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer f.Close()
+
+    if _, err := f.Write([]byte("aaaaaa\n")); err != nil {
+        t.Fatal(err)
+    }
+    if n, err := f.Write([]byte("bbbbbb\n")); err == nil || n != 7 {
+        t.Fatal("滚动失败时应该返回错误并写入原文件", n, err)
+    }
+    bs, err := ioutil.ReadFile(name)
+    if err != nil || string(bs) != "aaaaaa\nbbbbbb\n" {
+        t.Fatal("滚动失败后应该继续写入原文件", string(bs), err)
+    }
+
+    // 恢复后可以正常滚动
+    if err := os.RemoveAll(name + ".1"); err != nil {
+        t.Fatal(err)
+    }
+    if _, err := f.Write([]byte("cccccc\n")); err != nil {
+        t.Fatal(err)
+    }
+    for name, expect := range map[string]string{name: "cccccc\n", name + ".1": "aaaaaa\nbbbbbb\n"} {
+        bs, err := ioutil.ReadFile(name)
+        if err != nil || string(bs) != expect {
+            t.Fatal("滚动错误", name, string(bs), err)
+        }
+    }
+}
@@ -0,0 +1,215 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  慢请求
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "bytes"
+    "runtime"
+    "strconv"
+    "strings"
+    "sync/atomic"
+    "time"
+
+    "github.com/kataras/iris/v12"
+)
+
+// 默认慢请求阈值
+const DefaultSlowThreshold = time.Second
+
+// 默认慢请求报告的堆栈最大字节数
+const DefaultSlowStackSize = 8 << 10
+
+// 默认慢请求堆栈采样的最小间隔
+const DefaultSlowStackInterval = time.Minute
+
+// 慢请求数量
+var slowRequestCount uint64
+
+// 路由的慢请求阈值, key为路径前缀
+var routeSlowThresholds = map[string]time.Duration{}
+
+// 设置了慢请求阈值的路径前缀
+var slowThresholdPrefixes routePrefixes
+
+// 慢请求配置
+type SlowRequestConfig struct {
+    // 慢请求阈值, 为0时使用 DefaultSlowThreshold, 可以使用 SetRouteSlowThreshold 设置路由的阈值
+    Threshold time.Duration
+    // 报告样式, 为空时使用 DefaultLayoutWithHeader
+    Layout string
+    // 是否采样堆栈, 采样需要获取所有goroutine的堆栈, 开销较大, 默认关闭
+    // 关闭时不会在每个请求中获取goroutine的id和启动定时器
+    SampleStack bool
+    // 堆栈最大字节数, 为0时使用 DefaultSlowStackSize
+    StackSize int
+    // 两次堆栈采样的最小间隔, 为0时使用 DefaultSlowStackInterval, 为负数时不限制
+    // 间隔内的慢请求仍然会输出报告, 但是不包含堆栈
+    StackInterval time.Duration
+}
+
+// 设置路由的慢请求阈值, 请求路径为path或在path下时使用, 有多个匹配时使用最长的前缀
+// 如果threshold<=0则删除该路由的设置
+func SetRouteSlowThreshold(path string, threshold time.Duration) {
+    if threshold <= 0 {
+        delete(routeSlowThresholds, path)
+    } else {
+        routeSlowThresholds[path] = threshold
+    }
+
+    prefixes := make([]string, 0, len(routeSlowThresholds))
+    for p := range routeSlowThresholds {
+        prefixes = append(prefixes, p)
+    }
+    slowThresholdPrefixes = newRoutePrefixes(prefixes)
+}
+
+// 返回慢请求数量
+func GetSlowRequestCount() uint64 {
+    return atomic.LoadUint64(&slowRequestCount)
+}
+
+// 获取请求的慢请求阈值
+func getSlowThreshold(ctx iris.Context, def time.Duration) time.Duration {
+    if p, ok := slowThresholdPrefixes.match(ctx.Path()); ok {
+        return routeSlowThresholds[p]
+    }
+    return def
+}
+
+// 慢请求中间件, 请求处理时间达到阈值时输出详细报告并增加慢请求计数
+// 报告包含样式输出的请求信息, 处理程序名(控制器方法), 开启 SampleStack 时还包含达到阈值时处理请求的goroutine的堆栈
+// 如果log实现了 Warn(v ...interface{}) 会使用Warn输出
+func SlowRequestMiddleware(log interface{ Info(v ...interface{}) }, conf SlowRequestConfig) func(ctx iris.Context) {
+    if conf.Threshold <= 0 {
+        conf.Threshold = DefaultSlowThreshold
+    }
+    if conf.Layout == "" {
+        conf.Layout = DefaultLayoutWithHeader
+    }
+    if conf.StackSize <= 0 {
+        conf.StackSize = DefaultSlowStackSize
+    }
+    if conf.StackInterval == 0 {
+        conf.StackInterval = DefaultSlowStackInterval
+    }
+    // 上次采样堆栈的时间
+    var lastStackTime int64
+    // 检查距离上次采样是否已经达到间隔
+    stackAllowed := func(now int64) (int64, bool) {
+        last := atomic.LoadInt64(&lastStackTime)
+        return last, conf.StackInterval < 0 || last == 0 || now-last >= int64(conf.StackInterval)
+    }
+    layout, _ := compileLayout(conf.Layout, false)
+    logger := toLogger(log)
+
+    return func(ctx iris.Context) {
+        SetStartTime(ctx)
+        CaptureBody(ctx)
+        threshold := getSlowThreshold(ctx, conf.Threshold)
+
+        // 达到阈值时采样处理请求的goroutine的堆栈, 间隔内只有一个请求会采样
+        var stack []byte
+        var timer *time.Timer
+        var done chan struct{}
+        if conf.SampleStack {
+            if _, ok := stackAllowed(time.Now().UnixNano()); ok {
+                gid := currentGoroutineID()
+                done = make(chan struct{})
+                timer = time.AfterFunc(threshold, func() {
+                    defer close(done)
+                    now := time.Now().UnixNano()
+                    if last, ok := stackAllowed(now); ok && atomic.CompareAndSwapInt64(&lastStackTime, last, now) {
+                        stack = sampleGoroutineStack(gid, conf.StackSize)
+                    }
+                })
+            }
+        }
+
+        ctx.Next()
+
+        if timer != nil && !timer.Stop() {
+            <-done
+        }
+
+        latency := GetLatency(ctx)
+        if latency < threshold {
+            return
+        }
+        atomic.AddUint64(&slowRequestCount, 1)
+        logger.Warn(makeSlowReport(ctx, layout, latency, threshold, stack))
+    }
+}
+
+// 生成慢请求报告
+func makeSlowReport(ctx iris.Context, layout *Layout, latency, threshold time.Duration, stack []byte) string {
+    var buff bytes.Buffer
+    buff.WriteString("slow request: ")
+    buff.WriteString(latency.String())
+    buff.WriteString(" >= ")
+    buff.WriteString(threshold.String())
+    buff.WriteString("\n")
+    buff.WriteString(layout.Render(ctx))
+
+    buff.WriteString("\nhandler: ")
+    if fn, ok := formatFlags["controller"]; ok && fn(ctx, "") != "" {
+        buff.WriteString(fn(ctx, ""))
+        if fn, ok := formatFlags["control_method"]; ok {
+            buff.WriteString(".")
+            buff.WriteString(fn(ctx, ""))
+        }
+    } else {
+        buff.WriteString(ctx.HandlerName())
+    }
+
+    if len(stack) > 0 {
+        buff.WriteString("\nstack:\n")
+        buff.Write(stack)
+    }
+    return buff.String()
+}
+
+// 获取当前goroutine的id
+func currentGoroutineID() uint64 {
+    var buf [64]byte
+    n := runtime.Stack(buf[:], false)
+    // goroutine 123 [running]:
+    s := strings.TrimPrefix(string(buf[:n]), "goroutine ")
+    if k := strings.IndexByte(s, ' '); k != -1 {
+        s = s[:k]
+    }
+    id, _ := strconv.ParseUint(s, 10, 64)
+    return id
+}
+
+// 采样指定goroutine的堆栈, 最多返回max字节
+func sampleGoroutineStack(gid uint64, max int) []byte {
+    buf := make([]byte, 1<<20)
+    for {
+        n := runtime.Stack(buf, true)
+        if n < len(buf) || len(buf) >= 64<<20 {
+            buf = buf[:n]
+            break
+        }
+        buf = make([]byte, len(buf)*2)
+    }
+
+    prefix := []byte("goroutine " + strconv.FormatUint(gid, 10) + " [")
+    start := bytes.Index(buf, prefix)
+    if start == -1 {
+        return nil
+    }
+    stack := buf[start:]
+    if end := bytes.Index(stack, []byte("\n\n")); end != -1 {
+        stack = stack[:end]
+    }
+    if len(stack) > max {
+        stack = stack[:max]
+    }
+    return append(([]byte)(nil), stack...)
+}
@@ -0,0 +1,91 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/kataras/iris/v12"
+)
+
+type testInfoLogger []string
+
+func (m *testInfoLogger) Info(v ...interface{}) {
+    *m = append(*m, v[0].(string))
+}
+
+func TestSlowRequestMiddleware(t *testing.T) {
+    SetRouteSlowThreshold("/slow", 20*time.Millisecond)
+    defer SetRouteSlowThreshold("/slow", 0)
+
+    var log testInfoLogger
+    app := iris.New()
+    app.Use(SlowRequestMiddleware(&log, SlowRequestConfig{Threshold: time.Hour, SampleStack: true}))
+    app.Get("/{any:path}", func(ctx iris.Context) {
+        if ctx.Path() == "/slow" {
+            time.Sleep(50 * time.Millisecond)
+        }
+    })
+    if err := app.Build(); err != nil {
+        t.Fatal(err)
+    }
+
+    count := GetSlowRequestCount()
+    app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/fast", nil))
+    app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/slow", nil))
+
+    if GetSlowRequestCount() != count+1 || len(log) != 1 {
+        t.Fatal("慢请求计数错误", GetSlowRequestCount()-count, len(log))
+    }
+    if !strings.Contains(log[0], "/slow") || !strings.Contains(log[0], "time.Sleep") {
+        t.Fatal("慢请求报告错误", log[0])
+    }
+}
+
+func TestSlowRequestStackSampling(t *testing.T) {
+    tests := []struct {
+        name   string
+        conf   SlowRequestConfig
+        stacks int
+    }{
+        {"默认不采样堆栈", SlowRequestConfig{Threshold: 10 * time.Millisecond}, 0},
+        {"间隔内只采样一次", SlowRequestConfig{Threshold: 10 * time.Millisecond, SampleStack: true}, 1},
+        {"不限制间隔", SlowRequestConfig{Threshold: 10 * time.Millisecond, SampleStack: true, StackInterval: -1}, 3},
+    }
+    for _, tt := range tests {
+        var log testInfoLogger
+        app := iris.New()
+        app.Use(SlowRequestMiddleware(&log, tt.conf))
+        app.Get("/", func(ctx iris.Context) {
+            time.Sleep(30 * time.Millisecond)
+        })
+        if err := app.Build(); err != nil {
+            t.Fatal(err)
+        }
+        for i := 0; i < 3; i++ {
+            app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+        }
+
+        if len(log) != 3 {
+            t.Fatal(tt.name, "慢请求报告数量错误", len(log))
+        }
+        stacks := 0
+        for _, s := range log {
+            if strings.Contains(s, "\nstack:\n") {
+                stacks++
+            }
+        }
+        if stacks != tt.stacks {
+            t.Fatal(tt.name, "堆栈采样次数错误", stacks)
+        }
+    }
+}
@@ -0,0 +1,131 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  结构化的请求信息
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "encoding/json"
+    "io"
+    "sync"
+
+    "github.com/kataras/iris/v12"
+
+    "github.com/zlyuancn/ziris"
+)
+
+// 延迟时间的毫秒数字段
+const LatencyMsField = "latency_ms"
+
+// 结构化信息的字段顺序, 字段名和样式标记同名
+var fieldOrder = []string{
+    string(StatusFlag),
+    string(LatencyFlag),
+    LatencyMsField,
+    string(IPFlag),
+    string(MethodFlag),
+    string(PathFlag),
+    string(FullPathFlag),
+    string(RequestIDFlag),
+    string(HeaderFlag),
+    string(BodyFlag),
+    string(RespSizeFlag),
+    string(RespBodyFlag),
+}
+
+// 结构化的请求信息
+type Fields map[string]interface{}
+
+// 按照固定顺序返回键值对, 如 [status 200 latency 1ms ...], 可以直接传给结构化日志
+func (m Fields) KeyValues() []interface{} {
+    out := make([]interface{}, 0, len(m)*2)
+    for _, k := range fieldOrder {
+        if v, ok := m[k]; ok {
+            out = append(out, k, v)
+        }
+    }
+    return out
+}
+
+// 获取结构化的请求信息, 空的请求id, 请求体和响应体不会包含在内
+func GetFields(ctx iris.Context) Fields {
+    latency := GetLatency(ctx)
+    fields := Fields{
+        string(StatusFlag):   ctx.GetStatusCode(),
+        string(LatencyFlag):  latency.String(),
+        LatencyMsField:       float64(latency) / 1e6,
+        string(IPFlag):       ziris.ClientIP(ctx),
+        string(MethodFlag):   ctx.Method(),
+        string(PathFlag):     ctx.Path(),
+        string(FullPathFlag): RedactURI(ctx.Request().URL),
+        string(HeaderFlag):   getHeader(ctx),
+        string(RespSizeFlag): getRespSize(ctx),
+    }
+    if id := GetRequestID(ctx); id != "" {
+        fields[string(RequestIDFlag)] = id
+    }
+    if body := getBody(ctx); body != "" {
+        fields[string(BodyFlag)] = body
+    }
+    if body := getRespBody(ctx); body != "" {
+        fields[string(RespBodyFlag)] = body
+    }
+    return fields
+}
+
+// 结构化日志, 兼容 zap.SugaredLogger
+type StructuredLogger interface {
+    Infow(msg string, keysAndValues ...interface{})
+}
+
+// 分级结构化日志记录器
+type LeveledStructuredLogger interface {
+    StructuredLogger
+    Warnw(msg string, keysAndValues ...interface{})
+    Errorw(msg string, keysAndValues ...interface{})
+}
+
+// 结构化日志中间件, 用于以键值对的形式输出当前请求信息
+// 如果log实现了 LeveledStructuredLogger 会按状态码类别分级输出, 见 DefaultStatusLevels
+func StructuredLogMiddleware(log StructuredLogger) func(ctx iris.Context) {
+    return func(ctx iris.Context) {
+        SetStartTime(ctx)
+        CaptureBody(ctx)
+        ctx.Next()
+
+        kv := GetFields(ctx).KeyValues()
+        if l, ok := log.(LeveledStructuredLogger); ok {
+            switch DefaultStatusLevels[ctx.GetStatusCode()/100] {
+            case LevelWarn:
+                l.Warnw("access", kv...)
+                return
+            case LevelError:
+                l.Errorw("access", kv...)
+                return
+            }
+        }
+        log.Infow("access", kv...)
+    }
+}
+
+// json日志中间件, 每个请求会以一行json的形式写入w
+func JsonLogMiddleware(w io.Writer) func(ctx iris.Context) {
+    var mx sync.Mutex
+    return func(ctx iris.Context) {
+        SetStartTime(ctx)
+        CaptureBody(ctx)
+        ctx.Next()
+
+        bs, err := json.Marshal(GetFields(ctx))
+        if err != nil {
+            return
+        }
+        mx.Lock()
+        _, _ = w.Write(append(bs, '\n'))
+        mx.Unlock()
+    }
+}
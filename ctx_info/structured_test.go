@@ -0,0 +1,114 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "bytes"
+    "encoding/json"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/kataras/iris/v12"
+)
+
+func TestJsonLogMiddleware(t *testing.T) {
+    var buff bytes.Buffer
+    app := iris.New()
+    app.Use(RequestIDMiddleware(), JsonLogMiddleware(&buff))
+    app.Post("/{any:path}", func(ctx iris.Context) {
+        var v map[string]interface{}
+        _ = ctx.ReadJSON(&v)
+        ctx.StatusCode(201)
+        _, _ = ctx.WriteString("ok")
+    })
+    if err := app.Build(); err != nil {
+        t.Fatal(err)
+    }
+
+    req := httptest.NewRequest("POST", "/a?b=1", strings.NewReader(`{"name":"a"}`))
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set("Authorization", "Bearer xxx")
+    req.Header.Set(RequestIDHeader, "rid")
+    app.ServeHTTP(httptest.NewRecorder(), req)
+
+    line := buff.String()
+    if strings.Count(line, "\n") != 1 || !strings.HasSuffix(line, "\n") {
+        t.Fatal("应该输出一行json", line)
+    }
+    var fields map[string]interface{}
+    if err := json.Unmarshal([]byte(line), &fields); err != nil {
+        t.Fatal(err)
+    }
+    expect := map[string]interface{}{
+        "status":     float64(201),
+        "ip":         "192.0.2.1",
+        "method":     "POST",
+        "path":       "/a",
+        "fullpath":   "/a?b=1",
+        "request_id": "rid",
+        "body":       `{"name":"a"}`,
+        "resp_size":  float64(2),
+    }
+    for k, v := range expect {
+        if fields[k] != v {
+            t.Fatal("字段错误", k, fields[k])
+        }
+    }
+    if header, _ := fields["header"].(map[string]interface{}); header["Authorization"] != DefaultRedactMask {
+        t.Fatal("header应该被脱敏", fields["header"])
+    }
+    if _, ok := fields["latency_ms"].(float64); !ok {
+        t.Fatal("latency_ms应该是数字", fields["latency_ms"])
+    }
+}
+
+type testStructuredLogger struct {
+    level string
+    msg   string
+    kv    []interface{}
+}
+
+func (m *testStructuredLogger) Infow(msg string, kv ...interface{}) {
+    m.level, m.msg, m.kv = "info", msg, kv
+}
+func (m *testStructuredLogger) Warnw(msg string, kv ...interface{}) {
+    m.level, m.msg, m.kv = "warn", msg, kv
+}
+func (m *testStructuredLogger) Errorw(msg string, kv ...interface{}) {
+    m.level, m.msg, m.kv = "error", msg, kv
+}
+
+func TestStructuredLogMiddleware(t *testing.T) {
+    log := &testStructuredLogger{}
+    app := iris.New()
+    app.Use(StructuredLogMiddleware(log))
+    app.Get("/{any:path}", func(ctx iris.Context) {
+        ctx.StatusCode(404)
+    })
+    if err := app.Build(); err != nil {
+        t.Fatal(err)
+    }
+    app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/a", nil))
+
+    if log.level != "warn" || log.msg != "access" {
+        t.Fatal("日志级别错误", log.level, log.msg)
+    }
+    // 键值对按照 fieldOrder 排列, 空的请求id和请求体不包含在内
+    var keys []string
+    for i := 0; i < len(log.kv); i += 2 {
+        keys = append(keys, log.kv[i].(string))
+    }
+    if s := strings.Join(keys, ","); s != "status,latency,latency_ms,ip,method,path,fullpath,header,resp_size" {
+        t.Fatal("键值对顺序错误", s)
+    }
+    if log.kv[1] != 404 {
+        t.Fatal("状态码错误", log.kv[1])
+    }
+}
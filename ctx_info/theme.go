@@ -0,0 +1,175 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  颜色主题
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/kataras/iris/v12"
+
+    "github.com/zlyuancn/ziris"
+)
+
+// 延迟时间区间, 延迟时间达到 Threshold 时使用 Color
+type LatencyBand struct {
+    Threshold time.Duration
+    Color     ziris.ColorType
+}
+
+// 颜色主题
+type Theme struct {
+    // 状态码类别的颜色, key为状态码的百位数, 如 2 表示 2xx
+    StatusColors map[int]ziris.ColorType
+    // 请求方法的颜色, key为大写的请求方法
+    MethodColors map[string]ziris.ColorType
+    // 延迟时间区间, 使用达到的最大阈值的颜色, 一个都没有达到时不着色
+    LatencyBands []LatencyBand
+}
+
+// 默认颜色主题
+func DefaultTheme() *Theme {
+    return &Theme{
+        StatusColors: map[int]ziris.ColorType{
+            1: ziris.ColorBlue,
+            2: ziris.ColorCyan,
+            3: ziris.ColorYellow,
+            4: ziris.ColorRed,
+            5: ziris.ColorRed,
+        },
+        MethodColors: map[string]ziris.ColorType{
+            "GET":     ziris.ColorCyan,
+            "POST":    ziris.ColorBlue,
+            "PUT":     ziris.ColorYellow,
+            "DELETE":  ziris.ColorRed,
+            "HEAD":    ziris.ColorYellow,
+            "PATCH":   ziris.ColorYellow,
+            "OPTIONS": ziris.ColorYellow,
+        },
+        LatencyBands: []LatencyBand{
+            {Threshold: time.Second, Color: ziris.ColorYellow},
+            {Threshold: 2 * time.Second, Color: ziris.ColorRed},
+        },
+    }
+}
+
+var theme = DefaultTheme()
+
+// 路由的延迟时间区间, key为路径前缀
+var routeLatencyBands = map[string][]LatencyBand{}
+
+// 设置了延迟时间区间的路径前缀
+var latencyBandPrefixes routePrefixes
+
+// 设置颜色主题, 传入nil表示使用默认主题
+// 主题会被复制, 设置后修改t不会影响使用中的主题
+func SetTheme(t *Theme) {
+    if t == nil {
+        t = DefaultTheme()
+    }
+    c := &Theme{
+        StatusColors: make(map[int]ziris.ColorType, len(t.StatusColors)),
+        MethodColors: make(map[string]ziris.ColorType, len(t.MethodColors)),
+        LatencyBands: append(([]LatencyBand)(nil), t.LatencyBands...),
+    }
+    for k, v := range t.StatusColors {
+        c.StatusColors[k] = v
+    }
+    for k, v := range t.MethodColors {
+        c.MethodColors[strings.ToUpper(k)] = v
+    }
+    sortLatencyBands(c.LatencyBands)
+    theme = c
+}
+
+// 设置路由的延迟时间区间, 请求路径为path或在path下时使用, 有多个匹配时使用最长的前缀
+// 如 /slow 匹配 /slow 和 /slow/a, 但不匹配 /slowpoke
+// 如果不传入bands则删除该路由的设置
+func SetRouteLatencyBands(path string, bands ...LatencyBand) {
+    if len(bands) == 0 {
+        delete(routeLatencyBands, path)
+    } else {
+        bands = append(([]LatencyBand)(nil), bands...)
+        sortLatencyBands(bands)
+        routeLatencyBands[path] = bands
+    }
+
+    prefixes := make([]string, 0, len(routeLatencyBands))
+    for p := range routeLatencyBands {
+        prefixes = append(prefixes, p)
+    }
+    latencyBandPrefixes = newRoutePrefixes(prefixes)
+}
+
+func sortLatencyBands(bands []LatencyBand) {
+    sort.Slice(bands, func(i, j int) bool {
+        return bands[i].Threshold < bands[j].Threshold
+    })
+}
+
+// 路径前缀列表, 按长度从长到短排列, 用于查找最长的匹配
+type routePrefixes []string
+
+func newRoutePrefixes(prefixes []string) routePrefixes {
+    out := append(routePrefixes(nil), prefixes...)
+    sort.SliceStable(out, func(i, j int) bool {
+        return len(strings.TrimSuffix(out[i], "/")) > len(strings.TrimSuffix(out[j], "/"))
+    })
+    return out
+}
+
+// 返回path匹配的最长前缀, 没有匹配返回false
+func (m routePrefixes) match(path string) (string, bool) {
+    for _, p := range m {
+        if matchPathPrefix(path, p) {
+            return p, true
+        }
+    }
+    return "", false
+}
+
+// 按路径段检查path是否为prefix或在prefix下, 如 /a 匹配 /a 和 /a/b, 但不匹配 /ab
+func matchPathPrefix(path, prefix string) bool {
+    prefix = strings.TrimSuffix(prefix, "/")
+    if !strings.HasPrefix(path, prefix) {
+        return false
+    }
+    return len(path) == len(prefix) || path[len(prefix)] == '/'
+}
+
+// 获取请求使用的延迟时间区间
+func getLatencyBands(ctx iris.Context) []LatencyBand {
+    if p, ok := latencyBandPrefixes.match(ctx.Path()); ok {
+        return routeLatencyBands[p]
+    }
+    return theme.LatencyBands
+}
+
+// 获取状态码的颜色
+func statusColor(code int) ziris.ColorType {
+    return theme.StatusColors[code/100]
+}
+
+// 获取请求方法的颜色
+func methodColor(method string) ziris.ColorType {
+    return theme.MethodColors[strings.ToUpper(method)]
+}
+
+// 获取延迟时间的颜色
+func latencyColor(ctx iris.Context, latency time.Duration) ziris.ColorType {
+    color := ziris.ColorDefault
+    for _, band := range getLatencyBands(ctx) {
+        if latency < band.Threshold {
+            break
+        }
+        color = band.Color
+    }
+    return color
+}
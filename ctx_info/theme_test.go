@@ -0,0 +1,103 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "testing"
+    "time"
+
+    "github.com/kataras/iris/v12"
+
+    "github.com/zlyuancn/ziris"
+)
+
+func TestRouteLatencyBands(t *testing.T) {
+    SetRouteLatencyBands("/slow", LatencyBand{Threshold: time.Millisecond, Color: ziris.ColorMagenta})
+    defer SetRouteLatencyBands("/slow")
+
+    testRequest(t, "GET", "/slow/a", func(ctx iris.Context) {}, func(ctx iris.Context) {
+        if c := latencyColor(ctx, 2*time.Millisecond); c != ziris.ColorMagenta {
+            t.Fatal("颜色错误", c)
+        }
+        if c := latencyColor(ctx, time.Microsecond); c != ziris.ColorDefault {
+            t.Fatal("颜色错误", c)
+        }
+    })
+    testRequest(t, "GET", "/slowpoke", func(ctx iris.Context) {}, func(ctx iris.Context) {
+        if c := latencyColor(ctx, 2*time.Millisecond); c != ziris.ColorDefault {
+            t.Fatal("不应该匹配不完整的路径段", c)
+        }
+    })
+    testRequest(t, "GET", "/fast", func(ctx iris.Context) {}, func(ctx iris.Context) {
+        if c := latencyColor(ctx, 1500*time.Millisecond); c != ziris.ColorYellow {
+            t.Fatal("颜色错误", c)
+        }
+    })
+}
+
+func TestMatchRoutePrefix(t *testing.T) {
+    prefixes := newRoutePrefixes([]string{"/", "/slow", "/slow/a/", "/api/v1"})
+    tests := []struct {
+        path   string
+        expect string
+    }{
+        {"/", "/"},
+        {"/slow", "/slow"},
+        {"/slow/", "/slow"},
+        {"/slow/b", "/slow"},
+        {"/slowpoke", "/"},
+        {"/slow/a", "/slow/a/"},
+        {"/slow/a/b", "/slow/a/"},
+        {"/slow/ab", "/slow"},
+        {"/api/v1/user", "/api/v1"},
+        {"/api/v10", "/"},
+    }
+    for _, tt := range tests {
+        if p, _ := prefixes.match(tt.path); p != tt.expect {
+            t.Fatal(tt.path, "匹配错误", p)
+        }
+    }
+    if _, ok := newRoutePrefixes([]string{"/slow"}).match("/slowpoke"); ok {
+        t.Fatal("不应该匹配不完整的路径段")
+    }
+}
+
+func TestSetTheme(t *testing.T) {
+    defer SetTheme(nil)
+
+    th := DefaultTheme()
+    th.MethodColors["GET"] = ziris.ColorMagenta
+    th.LatencyBands = []LatencyBand{
+        {Threshold: 2 * time.Second, Color: ziris.ColorRed},
+        {Threshold: time.Second, Color: ziris.ColorYellow},
+    }
+    SetTheme(th)
+
+    // 修改传入的主题不会影响使用中的主题
+    th.StatusColors[2] = ziris.ColorBlue
+    th.MethodColors["GET"] = ziris.ColorBlue
+    th.LatencyBands[0].Color = ziris.ColorBlue
+    if th.LatencyBands[0].Threshold != 2*time.Second {
+        t.Fatal("不应该修改传入的主题")
+    }
+    if c := statusColor(200); c != ziris.ColorCyan {
+        t.Fatal("颜色错误", c)
+    }
+    if c := methodColor("get"); c != ziris.ColorMagenta {
+        t.Fatal("颜色错误", c)
+    }
+    testRequest(t, "GET", "/a", func(ctx iris.Context) {}, func(ctx iris.Context) {
+        if c := latencyColor(ctx, 1500*time.Millisecond); c != ziris.ColorYellow {
+            t.Fatal("颜色错误", c)
+        }
+        if c := latencyColor(ctx, 3*time.Second); c != ziris.ColorRed {
+            t.Fatal("颜色错误", c)
+        }
+    })
+}
@@ -0,0 +1,124 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  阶段耗时
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "strings"
+    "time"
+
+    "github.com/kataras/iris/v12"
+)
+
+// 阶段耗时标记
+const TimingsField = "ctx_timings"
+
+// 常用的阶段名
+const (
+    // 中间件, auto_route 会在调用控制器方法之前标记
+    MarkMiddleware = "middleware"
+    // 处理程序, auto_route 会在控制器方法返回之后标记
+    MarkHandler = "handler"
+    // 写入响应
+    MarkWrite = "write"
+)
+
+// 阶段耗时
+type Timing struct {
+    // 阶段名
+    Name string
+    // 阶段开始时相对于开始时间的偏移
+    Offset time.Duration
+    // 阶段耗时
+    Duration time.Duration
+}
+
+// 阶段耗时记录
+type timingRecorder struct {
+    timings []Timing
+    // 上一个标记结束时相对于开始时间的偏移
+    lastMark time.Duration
+}
+
+// 获取阶段耗时记录, 如果不存在且create为true则创建
+func getTimingRecorder(ctx iris.Context, create bool) *timingRecorder {
+    r, _ := ctx.Values().Get(TimingsField).(*timingRecorder)
+    if r == nil && create {
+        r = new(timingRecorder)
+        ctx.Values().Set(TimingsField, r)
+    }
+    return r
+}
+
+// 标记一个阶段结束, 阶段从上一个标记(没有则为开始时间)开始, 到现在结束
+// 需要先使用 SetStartTime 设置开始时间, 否则不会记录
+func Mark(ctx iris.Context, name string) {
+    start, ok := getStartTime(ctx)
+    if !ok {
+        return
+    }
+    now := time.Since(start)
+
+    r := getTimingRecorder(ctx, true)
+    r.timings = append(r.timings, Timing{Name: name, Offset: r.lastMark, Duration: now - r.lastMark})
+    r.lastMark = now
+}
+
+// 记录一个指定耗时的阶段, 它不影响 Mark 的阶段划分, 如
+//   t := time.Now()
+//   rows, err := db.Query(...)
+//   ctx_info.AddTiming(ctx, "db", time.Since(t))
+// 需要先使用 SetStartTime 设置开始时间, 否则不会记录
+func AddTiming(ctx iris.Context, name string, d time.Duration) {
+    start, ok := getStartTime(ctx)
+    if !ok {
+        return
+    }
+    r := getTimingRecorder(ctx, true)
+    r.timings = append(r.timings, Timing{Name: name, Offset: time.Since(start) - d, Duration: d})
+}
+
+// 开始一个阶段, 调用返回的函数结束阶段, 它不影响 Mark 的阶段划分, 如
+//   defer ctx_info.StartTiming(ctx, "db")()
+func StartTiming(ctx iris.Context, name string) func() {
+    t := time.Now()
+    return func() {
+        AddTiming(ctx, name, time.Since(t))
+    }
+}
+
+// 获取所有阶段耗时, 按记录顺序排列
+func GetTimings(ctx iris.Context) []Timing {
+    if r := getTimingRecorder(ctx, false); r != nil {
+        return append(([]Timing)(nil), r.timings...)
+    }
+    return nil
+}
+
+// 获取指定阶段的耗时, 同名阶段有多个时会累加
+func GetTiming(ctx iris.Context, name string) (time.Duration, bool) {
+    var d time.Duration
+    found := false
+    for _, t := range GetTimings(ctx) {
+        if t.Name == name {
+            d += t.Duration
+            found = true
+        }
+    }
+    return d, found
+}
+
+// 格式化所有阶段耗时, 如 middleware=1.2ms handler=3ms
+func formatTimings(ctx iris.Context, unit string) string {
+    timings := GetTimings(ctx)
+    texts := make([]string, len(timings))
+    for i, t := range timings {
+        texts[i] = t.Name + "=" + formatLatency(t.Duration, unit)
+    }
+    return strings.Join(texts, " ")
+}
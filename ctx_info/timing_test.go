@@ -0,0 +1,88 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package ctx_info
+
+import (
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/kataras/iris/v12"
+)
+
+func TestNestedStartTime(t *testing.T) {
+    var outerLatency, innerLatency time.Duration
+    var timings []Timing
+    app := iris.New()
+    app.Use(func(ctx iris.Context) {
+        if !SetStartTime(ctx) {
+            t.Fatal("第一次设置开始时间应该成功")
+        }
+        time.Sleep(10 * time.Millisecond)
+        Mark(ctx, "outer")
+        ctx.Next()
+        outerLatency = GetLatency(ctx)
+        timings = GetTimings(ctx)
+    })
+    app.Use(func(ctx iris.Context) {
+        if SetStartTime(ctx) {
+            t.Fatal("已经设置过开始时间时不应该覆盖")
+        }
+        time.Sleep(10 * time.Millisecond)
+        Mark(ctx, "inner")
+        ctx.Next()
+        innerLatency = GetLatency(ctx)
+    })
+    app.Get("/", func(ctx iris.Context) {
+        time.Sleep(10 * time.Millisecond)
+        Mark(ctx, "handler")
+    })
+    if err := app.Build(); err != nil {
+        t.Fatal(err)
+    }
+    app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+    if outerLatency < 30*time.Millisecond || innerLatency < 30*time.Millisecond || outerLatency < innerLatency {
+        t.Fatal("延迟时间应该从最外层的开始时间计算", outerLatency, innerLatency)
+    }
+    if len(timings) != 3 {
+        t.Fatal("阶段数量错误", timings)
+    }
+    for _, timing := range timings {
+        if timing.Offset < 0 || timing.Duration < 5*time.Millisecond {
+            t.Fatal("阶段耗时错误", timing)
+        }
+    }
+}
+
+func TestTimings(t *testing.T) {
+    testRequest(t, "GET", "/a", func(ctx iris.Context) {
+        time.Sleep(5 * time.Millisecond)
+        Mark(ctx, MarkMiddleware)
+        Mark(ctx, MarkHandler)
+    }, func(ctx iris.Context) {
+        timings := GetTimings(ctx)
+        if len(timings) != 2 || timings[0].Name != MarkMiddleware || timings[1].Name != MarkHandler {
+            t.Fatal("阶段错误", timings)
+        }
+        if timings[0].Duration < 5*time.Millisecond || timings[1].Offset != timings[0].Duration {
+            t.Fatal("阶段耗时错误", timings)
+        }
+        if d, ok := GetTiming(ctx, MarkHandler); !ok || d != timings[1].Duration {
+            t.Fatal("阶段耗时错误", d)
+        }
+        if s := GetInfoOfLayout(ctx, "%(timing:not_exists)s|%(timing:handler,ns)s"); !strings.HasPrefix(s, "|") || !strings.HasSuffix(s, "ns") {
+            t.Fatal("输出错误", s)
+        }
+        if latency, ok := LookupLatency(ctx); !ok || latency < timings[1].Offset+timings[1].Duration {
+            t.Fatal("延迟时间错误", latency)
+        }
+    })
+}
@@ -20,8 +20,8 @@ require (
 	github.com/onsi/gomega v1.8.1 // indirect
 	github.com/sergi/go-diff v1.1.0 // indirect
 	github.com/smartystreets/goconvey v1.6.4 // indirect
-	github.com/swaggo/http-swagger v0.0.0-20200103000832-0e9263c4b516
-	github.com/swaggo/swag v1.6.5 // indirect
+	github.com/swaggo/files v0.0.0-20190704085106-630677cd5c14
+	github.com/swaggo/swag v1.6.5
 	github.com/valyala/fasthttp v1.8.0 // indirect
 	github.com/xeipuuv/gojsonschema v1.2.0 // indirect
 	github.com/yalp/jsonpath v0.0.0-20180802001716-5cc68e5049a0 // indirect
@@ -34,6 +34,6 @@ require (
 	golang.org/x/sys v0.0.0-20200301040627-c5d0d7b4ec88 // indirect
 	golang.org/x/tools v0.0.0-20200228224639-71482053b885 // indirect
 	gopkg.in/ini.v1 v1.52.0 // indirect
-	gopkg.in/yaml.v2 v2.2.8 // indirect
+	gopkg.in/yaml.v2 v2.2.8
 	gopkg.in/yaml.v3 v3.0.0-20200121175148-a6ecf24a6d71 // indirect
 )
@@ -0,0 +1,112 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  控制器指标
+-------------------------------------------------
+*/
+
+package metrics
+
+import (
+    "strconv"
+    "sync"
+
+    "github.com/kataras/iris/v12"
+
+    "github.com/zlyuancn/ziris/auto_route"
+    "github.com/zlyuancn/ziris/ctx_info"
+)
+
+// 未定义的控制器方法使用的标签值, 避免路径参数导致标签数量失控
+const UndefinedControlMethod = "<undefined>"
+
+// 控制器指标
+type ControllerMetrics struct {
+    requests *CounterVec
+    latency  *HistogramVec
+    inFlight *GaugeVec
+    respSize *HistogramVec
+}
+
+// 创建控制器指标并注册到注册器中, namespace为指标名前缀, 可以为空
+func NewControllerMetrics(r *Registry, namespace string) *ControllerMetrics {
+    if namespace != "" {
+        namespace += "_"
+    }
+    labels := []string{"controller", "method", "verb"}
+    return &ControllerMetrics{
+        requests: r.NewCounterVec(namespace+"controller_requests_total", "控制器请求总数", append(labels, "code")...),
+        latency:  r.NewHistogramVec(namespace+"controller_request_duration_seconds", "控制器请求处理时间(秒)", DefaultLatencyBuckets, labels...),
+        inFlight: r.NewGaugeVec(namespace+"controller_requests_in_flight", "正在处理的控制器请求数", labels...),
+        respSize: r.NewHistogramVec(namespace+"controller_response_size_bytes", "控制器响应大小(字节)", DefaultSizeBuckets, labels...),
+    }
+}
+
+// 返回记录指标的控制器请求中间件, 它应该作为第一个中间件
+// 处理时间从开始时间算起, 如果外层中间件(如 ctx_info.LogMiddleware)已经设置了开始时间则不会覆盖
+func (m *ControllerMetrics) ReqMiddleware() auto_route.ReqMiddleware {
+    return func(ctx iris.Context, arg *auto_route.ReqArg) {
+        if _, ok := ctx_info.LookupLatency(ctx); !ok {
+            ctx_info.SetStartTime(ctx)
+        }
+
+        controller, verb := arg.ControllerName(), ctx.Method()
+        method := controlMethodLabel(ctx, arg)
+        m.inFlight.Inc(controller, method, verb)
+
+        arg.OnFinish(func(ctx iris.Context, arg *auto_route.ReqArg) {
+            m.inFlight.Dec(controller, method, verb)
+
+            code := ctx.GetStatusCode()
+            if arg.IsPanic() {
+                code = 500
+            }
+            // 中间件可能修改了控制器方法
+            method := controlMethodLabel(ctx, arg)
+            m.requests.Inc(controller, method, verb, strconv.Itoa(code))
+            if latency, ok := ctx_info.LookupLatency(ctx); ok {
+                m.latency.Observe(latency.Seconds(), controller, method, verb)
+            }
+            m.respSize.Observe(float64(responseSize(ctx)), controller, method, verb)
+        })
+    }
+}
+
+// 返回控制器方法标签值
+func controlMethodLabel(ctx iris.Context, arg *auto_route.ReqArg) string {
+    for _, s := range arg.AllowMethods() {
+        if s == ctx.Method() {
+            return arg.ControlMethod()
+        }
+    }
+    return UndefinedControlMethod
+}
+
+// 返回响应大小
+func responseSize(ctx iris.Context) int {
+    if rec, ok := ctx.IsRecording(); ok {
+        return len(rec.Body())
+    }
+    if n := ctx.ResponseWriter().Written(); n > 0 {
+        return n
+    }
+    return 0
+}
+
+var defaultControllerMetrics *ControllerMetrics
+var defaultControllerMetricsOnce sync.Once
+
+// 使用默认注册器记录指标的控制器请求中间件
+// 使用 app.Get("/metrics", metrics.Handler()) 输出指标
+func Middleware() auto_route.ReqMiddleware {
+    defaultControllerMetricsOnce.Do(func() {
+        defaultControllerMetrics = NewControllerMetrics(DefaultRegistry, "ziris")
+    })
+    return defaultControllerMetrics.ReqMiddleware()
+}
+
+// 输出默认注册器指标的iris处理程序
+func Handler() iris.Handler {
+    return DefaultRegistry.Handler()
+}
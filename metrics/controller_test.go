@@ -0,0 +1,89 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package metrics
+
+import (
+    "bytes"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/kataras/iris/v12"
+
+    "github.com/zlyuancn/ziris/auto_route"
+    "github.com/zlyuancn/ziris/ctx_info"
+)
+
+type TestMetricsController struct{}
+
+func (t *TestMetricsController) GetInfo(ctx iris.Context) {
+    _, _ = ctx.WriteString("info")
+}
+
+func (t *TestMetricsController) PostSave(ctx iris.Context) {
+    ctx.StatusCode(201)
+}
+
+func TestControllerMetrics(t *testing.T) {
+    r := NewRegistry()
+    m := NewControllerMetrics(r, "test")
+
+    var outerLatency time.Duration
+    app := iris.New()
+    app.Use(func(ctx iris.Context) {
+        ctx_info.SetStartTime(ctx)
+        time.Sleep(20 * time.Millisecond)
+        ctx.Next()
+        outerLatency = ctx_info.GetLatency(ctx)
+    })
+    auto_route.RegistryController(app, (*TestMetricsController)(nil), m.ReqMiddleware())
+    if err := app.Build(); err != nil {
+        t.Fatal(err)
+    }
+    for _, req := range [][2]string{
+        {"GET", "/test_metrics/info"},
+        {"GET", "/test_metrics/info"},
+        {"POST", "/test_metrics/save"},
+        {"GET", "/test_metrics/not_exists_1"},
+        {"GET", "/test_metrics/not_exists_2"},
+        {"POST", "/test_metrics/info"},
+    } {
+        app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(req[0], req[1], nil))
+    }
+    if outerLatency < 20*time.Millisecond {
+        t.Fatal("中间件不应该覆盖外层设置的开始时间", outerLatency)
+    }
+
+    var buff bytes.Buffer
+    if err := r.WriteText(&buff); err != nil {
+        t.Fatal(err)
+    }
+    text := buff.String()
+    for _, s := range []string{
+        `test_controller_requests_total{controller="test_metrics",method="info",verb="GET",code="200"} 2`,
+        `test_controller_requests_total{controller="test_metrics",method="save",verb="POST",code="201"} 1`,
+        `test_controller_requests_total{controller="test_metrics",method="<undefined>",verb="GET",code="400"} 2`,
+        `test_controller_requests_total{controller="test_metrics",method="<undefined>",verb="POST",code="400"} 1`,
+        `test_controller_requests_in_flight{controller="test_metrics",method="info",verb="GET"} 0`,
+        `test_controller_request_duration_seconds_count{controller="test_metrics",method="info",verb="GET"} 2`,
+        `test_controller_response_size_bytes_sum{controller="test_metrics",method="info",verb="GET"} 8`,
+    } {
+        if !strings.Contains(text, s) {
+            t.Fatal("没有找到指标", s, "\n", text)
+        }
+    }
+    if strings.Contains(text, "not_exists") {
+        t.Fatal("未定义的控制器方法应该使用 <undefined> 标签", text)
+    }
+    // 处理时间包含外层中间件的时间
+    if !strings.Contains(text, `test_controller_request_duration_seconds_bucket{controller="test_metrics",method="info",verb="GET",le="0.01"} 0`) {
+        t.Fatal("处理时间应该从外层设置的开始时间算起", text)
+    }
+}
@@ -0,0 +1,322 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  prometheus兼容的指标
+-------------------------------------------------
+*/
+
+package metrics
+
+import (
+    "bufio"
+    "fmt"
+    "io"
+    "math"
+    "sort"
+    "strconv"
+    "strings"
+    "sync"
+
+    "github.com/kataras/iris/v12"
+)
+
+// prometheus文本格式的 Content-Type
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// 默认的延迟时间桶(秒)
+var DefaultLatencyBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// 默认的大小桶(字节)
+var DefaultSizeBuckets = []float64{100, 1e3, 1e4, 1e5, 1e6, 1e7}
+
+// 默认注册器
+var DefaultRegistry = NewRegistry()
+
+type collector interface {
+    // 按照prometheus文本格式写入
+    write(w *bufio.Writer)
+}
+
+// 指标注册器
+type Registry struct {
+    mx         sync.Mutex
+    names      map[string]struct{}
+    collectors []collector
+}
+
+// 创建指标注册器
+func NewRegistry() *Registry {
+    return &Registry{names: make(map[string]struct{})}
+}
+
+func (m *Registry) register(name string, c collector) {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    if _, ok := m.names[name]; ok {
+        panic(fmt.Sprintf("指标 %s 已注册", name))
+    }
+    m.names[name] = struct{}{}
+    m.collectors = append(m.collectors, c)
+}
+
+// 按照prometheus文本格式写入所有指标
+func (m *Registry) WriteText(w io.Writer) error {
+    m.mx.Lock()
+    collectors := append(([]collector)(nil), m.collectors...)
+    m.mx.Unlock()
+
+    bw := bufio.NewWriter(w)
+    for _, c := range collectors {
+        c.write(bw)
+    }
+    return bw.Flush()
+}
+
+// 输出指标的iris处理程序, 如 app.Get("/metrics", registry.Handler())
+func (m *Registry) Handler() iris.Handler {
+    return func(ctx iris.Context) {
+        ctx.ContentType(ContentType)
+        _ = m.WriteText(ctx)
+    }
+}
+
+// 带标签的指标
+type vec struct {
+    name   string
+    help   string
+    typ    string
+    labels []string
+
+    mx     sync.Mutex
+    values map[string]*vecValue
+}
+
+type vecValue struct {
+    labelValues []string
+    value       float64
+    // 直方图数据
+    buckets []uint64
+    count   uint64
+}
+
+func newVec(name, help, typ string, labels []string) *vec {
+    return &vec{
+        name:   name,
+        help:   help,
+        typ:    typ,
+        labels: labels,
+        values: make(map[string]*vecValue),
+    }
+}
+
+// 获取标签值对应的数据, 调用者需要加锁
+func (m *vec) get(labelValues []string, bucketNum int) *vecValue {
+    if len(labelValues) != len(m.labels) {
+        panic(fmt.Sprintf("指标 %s 需要 %d 个标签值, 但是传入了 %d 个", m.name, len(m.labels), len(labelValues)))
+    }
+    key := strings.Join(labelValues, "\xff")
+    v, ok := m.values[key]
+    if !ok {
+        v = &vecValue{labelValues: append(([]string)(nil), labelValues...)}
+        if bucketNum > 0 {
+            v.buckets = make([]uint64, bucketNum)
+        }
+        m.values[key] = v
+    }
+    return v
+}
+
+// 返回排序后的数据, 调用者需要加锁
+func (m *vec) sortedValues() []*vecValue {
+    keys := make([]string, 0, len(m.values))
+    for k := range m.values {
+        keys = append(keys, k)
+    }
+    sort.Strings(keys)
+    out := make([]*vecValue, len(keys))
+    for i, k := range keys {
+        out[i] = m.values[k]
+    }
+    return out
+}
+
+func (m *vec) writeHeader(w *bufio.Writer) {
+    _, _ = fmt.Fprintf(w, "# HELP %s %s\n", m.name, escapeHelp(m.help))
+    _, _ = fmt.Fprintf(w, "# TYPE %s %s\n", m.name, m.typ)
+}
+
+func (m *vec) write(w *bufio.Writer) {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+
+    m.writeHeader(w)
+    for _, v := range m.sortedValues() {
+        writeSample(w, m.name, m.labels, v.labelValues, "", "", v.value)
+    }
+}
+
+// 计数器
+type CounterVec struct {
+    vec
+}
+
+// 创建并注册计数器
+func (m *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+    c := &CounterVec{vec: *newVec(name, help, "counter", labels)}
+    m.register(name, c)
+    return c
+}
+
+// 计数加1
+func (m *CounterVec) Inc(labelValues ...string) {
+    m.Add(1, labelValues...)
+}
+
+// 增加计数, v必须大于等于0
+func (m *CounterVec) Add(v float64, labelValues ...string) {
+    if v < 0 {
+        panic("计数器不能减少")
+    }
+    m.mx.Lock()
+    m.get(labelValues, 0).value += v
+    m.mx.Unlock()
+}
+
+// 仪表
+type GaugeVec struct {
+    vec
+}
+
+// 创建并注册仪表
+func (m *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+    g := &GaugeVec{vec: *newVec(name, help, "gauge", labels)}
+    m.register(name, g)
+    return g
+}
+
+// 设置值
+func (m *GaugeVec) Set(v float64, labelValues ...string) {
+    m.mx.Lock()
+    m.get(labelValues, 0).value = v
+    m.mx.Unlock()
+}
+
+// 增加值, v可以为负数
+func (m *GaugeVec) Add(v float64, labelValues ...string) {
+    m.mx.Lock()
+    m.get(labelValues, 0).value += v
+    m.mx.Unlock()
+}
+
+// 值加1
+func (m *GaugeVec) Inc(labelValues ...string) {
+    m.Add(1, labelValues...)
+}
+
+// 值减1
+func (m *GaugeVec) Dec(labelValues ...string) {
+    m.Add(-1, labelValues...)
+}
+
+// 直方图
+type HistogramVec struct {
+    vec
+    upperBounds []float64
+}
+
+// 创建并注册直方图, buckets为桶的上限, 必须是递增的
+func (m *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+    if !sort.Float64sAreSorted(buckets) {
+        panic(fmt.Sprintf("直方图 %s 的桶必须是递增的", name))
+    }
+    h := &HistogramVec{
+        vec:         *newVec(name, help, "histogram", labels),
+        upperBounds: append(([]float64)(nil), buckets...),
+    }
+    m.register(name, h)
+    return h
+}
+
+// 记录一个观察值
+func (m *HistogramVec) Observe(v float64, labelValues ...string) {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+
+    value := m.get(labelValues, len(m.upperBounds))
+    for i, upper := range m.upperBounds {
+        if v <= upper {
+            value.buckets[i]++
+        }
+    }
+    value.count++
+    value.value += v
+}
+
+func (m *HistogramVec) write(w *bufio.Writer) {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+
+    m.writeHeader(w)
+    for _, v := range m.sortedValues() {
+        for i, upper := range m.upperBounds {
+            writeSample(w, m.name+"_bucket", m.labels, v.labelValues, "le", formatFloat(upper), float64(v.buckets[i]))
+        }
+        writeSample(w, m.name+"_bucket", m.labels, v.labelValues, "le", "+Inf", float64(v.count))
+        writeSample(w, m.name+"_sum", m.labels, v.labelValues, "", "", v.value)
+        writeSample(w, m.name+"_count", m.labels, v.labelValues, "", "", float64(v.count))
+    }
+}
+
+// 写入一行数据, extraLabel不为空时会追加到标签末尾
+func writeSample(w *bufio.Writer, name string, labels, labelValues []string, extraLabel, extraValue string, value float64) {
+    _, _ = w.WriteString(name)
+    if len(labels) > 0 || extraLabel != "" {
+        _ = w.WriteByte('{')
+        for i, l := range labels {
+            if i > 0 {
+                _ = w.WriteByte(',')
+            }
+            _, _ = w.WriteString(l)
+            _, _ = w.WriteString(`="`)
+            _, _ = w.WriteString(escapeLabelValue(labelValues[i]))
+            _ = w.WriteByte('"')
+        }
+        if extraLabel != "" {
+            if len(labels) > 0 {
+                _ = w.WriteByte(',')
+            }
+            _, _ = w.WriteString(extraLabel)
+            _, _ = w.WriteString(`="`)
+            _, _ = w.WriteString(extraValue)
+            _ = w.WriteByte('"')
+        }
+        _ = w.WriteByte('}')
+    }
+    _ = w.WriteByte(' ')
+    _, _ = w.WriteString(formatFloat(value))
+    _ = w.WriteByte('\n')
+}
+
+func formatFloat(v float64) string {
+    switch {
+    case math.IsInf(v, 1):
+        return "+Inf"
+    case math.IsInf(v, -1):
+        return "-Inf"
+    case math.IsNaN(v):
+        return "NaN"
+    }
+    return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+var helpReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+func escapeLabelValue(s string) string {
+    return labelValueReplacer.Replace(s)
+}
+
+func escapeHelp(s string) string {
+    return helpReplacer.Replace(s)
+}
@@ -0,0 +1,45 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package metrics
+
+import (
+    "bytes"
+    "strings"
+    "testing"
+)
+
+func TestRegistryWriteText(t *testing.T) {
+    r := NewRegistry()
+    c := r.NewCounterVec("test_total", "测试计数", "a")
+    c.Inc(`x"y`)
+    c.Add(2, "b")
+    h := r.NewHistogramVec("test_seconds", "测试直方图", []float64{0.1, 1}, "a")
+    h.Observe(0.05, "b")
+    h.Observe(0.5, "b")
+
+    var buff bytes.Buffer
+    if err := r.WriteText(&buff); err != nil {
+        t.Fatal(err)
+    }
+    expect := `# HELP test_total 测试计数
+# TYPE test_total counter
+test_total{a="b"} 2
+test_total{a="x\"y"} 1
+# HELP test_seconds 测试直方图
+# TYPE test_seconds histogram
+test_seconds_bucket{a="b",le="0.1"} 1
+test_seconds_bucket{a="b",le="1"} 2
+test_seconds_bucket{a="b",le="+Inf"} 2
+test_seconds_sum{a="b"} 0.55
+test_seconds_count{a="b"} 2
+`
+    if strings.TrimSpace(buff.String()) != strings.TrimSpace(expect) {
+        t.Fatal("输出错误\n", buff.String())
+    }
+}
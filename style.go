@@ -0,0 +1,152 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  终端样式
+-------------------------------------------------
+*/
+
+package ziris
+
+import (
+    "fmt"
+    "io"
+    "os"
+    "regexp"
+    "strconv"
+    "strings"
+    "sync"
+)
+
+// 终端样式, 由多个SGR参数组成, 可以组合使用
+type Style []string
+
+var (
+    StyleBold      = Style{"1"} // 粗体
+    StyleDim       = Style{"2"} // 暗淡
+    StyleItalic    = Style{"3"} // 斜体
+    StyleUnderline = Style{"4"} // 下划线
+    StyleReverse   = Style{"7"} // 反色
+)
+
+// 前景色
+func Fg(color ColorType) Style {
+    if color == ColorDefault {
+        return Style{"39"}
+    }
+    return Style{strconv.Itoa(int(color))}
+}
+
+// 背景色
+func Bg(color ColorType) Style {
+    if color == ColorDefault {
+        return Style{"49"}
+    }
+    return Style{strconv.Itoa(int(color) + 10)}
+}
+
+// 256色前景色
+func Fg256(n uint8) Style {
+    return Style{"38;5;" + strconv.Itoa(int(n))}
+}
+
+// 256色背景色
+func Bg256(n uint8) Style {
+    return Style{"48;5;" + strconv.Itoa(int(n))}
+}
+
+// 真彩色前景色
+func FgRGB(r, g, b uint8) Style {
+    return Style{fmt.Sprintf("38;2;%d;%d;%d", r, g, b)}
+}
+
+// 真彩色背景色
+func BgRGB(r, g, b uint8) Style {
+    return Style{fmt.Sprintf("48;2;%d;%d;%d", r, g, b)}
+}
+
+// 组合样式, 返回一个新的样式
+// 如 StyleBold.Add(Fg(ColorRed), Bg(ColorWhite))
+func (m Style) Add(others ...Style) Style {
+    out := append(Style(nil), m...)
+    for _, s := range others {
+        out = append(out, s...)
+    }
+    return out
+}
+
+// 返回样式的控制码, 如 \x1b[1;31m
+func (m Style) Code() string {
+    if len(m) == 0 {
+        return ""
+    }
+    return "\x1b[" + strings.Join(m, ";") + "m"
+}
+
+// 使用样式渲染文本, 如果不输出颜色则直接返回文本
+func (m Style) Render(text string) string {
+    if !colorEnabled || len(m) == 0 {
+        return text
+    }
+    return m.Code() + text + "\x1b[0m"
+}
+
+// 使用样式渲染, 参数的处理方式和 fmt.Sprint 相同
+func (m Style) Sprint(a ...interface{}) string {
+    return m.Render(fmt.Sprint(a...))
+}
+
+// 使用样式渲染, 参数的处理方式和 fmt.Sprintf 相同
+func (m Style) Sprintf(format string, a ...interface{}) string {
+    return m.Render(fmt.Sprintf(format, a...))
+}
+
+var ansiParser = regexp.MustCompile("\x1b\\[[0-9;?]*[ -/]*[@-~]")
+
+// 去掉文本中的颜色控制码
+func StripColor(s string) string {
+    if !strings.Contains(s, "\x1b[") {
+        return s
+    }
+    return ansiParser.ReplaceAllString(s, "")
+}
+
+// 可以单独控制是否输出颜色的写入器, 不输出颜色时会去掉写入数据中的颜色控制码
+type ColorWriter struct {
+    w       io.Writer
+    mx      sync.Mutex
+    enabled bool
+}
+
+// 创建写入器, 如果w是终端并且没有设置环境变量 NO_COLOR 则输出颜色, 否则(如文件)不输出颜色
+func NewColorWriter(w io.Writer) *ColorWriter {
+    enabled := false
+    if f, ok := w.(*os.File); ok {
+        enabled = detectColorEnabled(f)
+    }
+    return &ColorWriter{w: w, enabled: enabled}
+}
+
+// 设置是否输出颜色
+func (m *ColorWriter) SetEnabled(enabled bool) {
+    m.mx.Lock()
+    m.enabled = enabled
+    m.mx.Unlock()
+}
+
+// 返回是否输出颜色
+func (m *ColorWriter) IsEnabled() bool {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    return m.enabled
+}
+
+func (m *ColorWriter) Write(p []byte) (int, error) {
+    if m.IsEnabled() {
+        return m.w.Write(p)
+    }
+    if _, err := m.w.Write(ansiParser.ReplaceAll(p, nil)); err != nil {
+        return 0, err
+    }
+    return len(p), nil
+}
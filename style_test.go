@@ -0,0 +1,44 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package ziris
+
+import (
+    "bytes"
+    "testing"
+)
+
+func TestStyle(t *testing.T) {
+    defer SetColorEnabled(IsColorEnabled())
+    SetColorEnabled(true)
+
+    s := StyleBold.Add(Fg(ColorRed), Bg256(236), FgRGB(1, 2, 3))
+    if text := s.Render("a"); text != "\x1b[1;31;48;5;236;38;2;1;2;3ma\x1b[0m" {
+        t.Fatalf("渲染错误 %q", text)
+    }
+    if text := StripColor(s.Render("a") + MakeColorText(ColorBlue, "b")); text != "ab" {
+        t.Fatalf("去掉颜色错误 %q", text)
+    }
+
+    SetColorEnabled(false)
+    if text := s.Render("a"); text != "a" {
+        t.Fatalf("不输出颜色时渲染错误 %q", text)
+    }
+}
+
+func TestColorWriter(t *testing.T) {
+    var buff bytes.Buffer
+    w := NewColorWriter(&buff)
+    if w.IsEnabled() {
+        t.Fatal("非终端写入器默认不应该输出颜色")
+    }
+    n, err := w.Write([]byte("\x1b[31mred\x1b[0m"))
+    if err != nil || n != 12 || buff.String() != "red" {
+        t.Fatalf("写入错误 %d %v %q", n, err, buff.String())
+    }
+}
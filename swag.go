@@ -9,25 +9,255 @@
 package ziris
 
 import (
+    "encoding/json"
+    "html/template"
+    "strings"
+
     "github.com/kataras/iris/v12"
-    httpSwagger "github.com/swaggo/http-swagger"
+    swaggerFiles "github.com/swaggo/files"
+    "github.com/swaggo/swag"
 )
 
+// swagger ui 配置
+type SwaggerConfig struct {
+    // 文档地址, 为空时使用Doc, 地址为 {path}/doc.json
+    URL string
+    // 文档来源, 会提供 {path}/doc.json 和 {path}/doc.yaml 两种格式
+    // 为nil时使用 swag 生成的文档, 需要导入 swag 生成的 docs 包
+    Doc SwaggerDoc
+    // 页面标题, 默认为 Swagger UI
+    Title string
+    // 关闭深度链接, 深度链接会将展开的标签和操作记录到地址中, 方便分享
+    DisableDeepLinking bool
+    // 默认展开方式, 可以是 list, full, none, 默认为 list
+    DocExpansion string
+    // 模型默认展开深度, 为nil时为1, 为-1时隐藏模型
+    DefaultModelsExpandDepth *int
+    // 保存授权信息, 刷新页面后不会丢失
+    PersistAuthorization bool
+    // OAuth 客户端配置, 为nil时不初始化
+    OAuth *SwaggerOAuthConfig
+    // 多个文档, 可以在页面顶部切换, 设置后会忽略URL和Doc, 也不会提供 {path}/doc.json
+    Specs []SwaggerSpec
+    // 默认选中的文档名, 为空时选中第一个
+    PrimarySpec string
+}
+
+// swagger ui 的 OAuth 客户端配置
+type SwaggerOAuthConfig struct {
+    ClientID     string   `json:"clientId,omitempty"`
+    ClientSecret string   `json:"clientSecret,omitempty"`
+    Realm        string   `json:"realm,omitempty"`
+    AppName      string   `json:"appName,omitempty"`
+    Scopes       []string `json:"scopes,omitempty"`
+    // 额外的授权请求参数
+    AdditionalQueryStringParams map[string]string `json:"additionalQueryStringParams,omitempty"`
+    // 授权码模式使用PKCE
+    UsePkceWithAuthorizationCodeGrant bool `json:"usePkceWithAuthorizationCodeGrant,omitempty"`
+}
+
+// swagger 文档
+type SwaggerSpec struct {
+    // 文档名, 如 v1
+    Name string `json:"name"`
+    // 文档地址, 为空时使用Doc, 地址为 {path}/{name}.json, URL和Doc都为空时会panic
+    URL string `json:"url"`
+    // 文档来源, 会提供 {path}/{name}.json 和 {path}/{name}.yaml 两种格式, 文档名应该只包含字母, 数字, 点, 下划线和减号
+    Doc SwaggerDoc `json:"-"`
+}
+
 // 安装swagger
 // swag i -g xxx.go -o ./docs
 // swag i -g xxx.go -o ./docs --parseDependency   解析外部依赖, 注意, 非常慢
 func SetupSwagger(ver iris.Party, path string) {
+    SetupSwaggerWithConfig(ver, path, SwaggerConfig{})
+}
+
+// 使用配置安装swagger, 如在一个页面中切换多个文档
+//   ziris.SetupSwaggerWithConfig(app, "/swagger", ziris.SwaggerConfig{
+//       Specs: []ziris.SwaggerSpec{
+//           {Name: "v1", Doc: ziris.SwaggerDocFile("./docs/v1/swagger.yaml")},
+//           {Name: "v2", URL: "/v2/doc.json"},
+//       },
+//   })
+// 多次调用时使用不同的path即可在一个进程中提供多个独立的文档
+func SetupSwaggerWithConfig(ver iris.Party, path string, conf SwaggerConfig) {
     p := ver.GetRelPath()
     if p == "/" {
         p = ""
     }
+    prefix := p + path + "/"
+
+    // 文档文件名 => 文档
+    docs := map[string]SwaggerDoc{}
+    if len(conf.Specs) == 0 && conf.URL == "" {
+        conf.URL = prefix + "doc.json"
+        docs["doc"] = conf.Doc
+        if conf.Doc == nil {
+            docs["doc"] = func() ([]byte, error) {
+                doc, err := swag.ReadDoc()
+                return []byte(doc), err
+            }
+        }
+    }
+    specs := make([]SwaggerSpec, len(conf.Specs))
+    for i, spec := range conf.Specs {
+        if spec.URL == "" {
+            if spec.Doc == nil {
+                panic("swagger文档 " + spec.Name + " 没有设置URL或Doc")
+            }
+            spec.URL = prefix + spec.Name + ".json"
+            docs[spec.Name] = spec.Doc
+        }
+        specs[i] = spec
+    }
+    conf.Specs = specs
+
+    index := template.Must(template.New("swagger_index.html").Parse(swaggerIndexTempl))
+    data := makeSwaggerIndexData(conf)
+
+    files := *swaggerFiles.Handler
+    files.Prefix = prefix
 
     ver.Get(path, func(ctx iris.Context) {
-        ctx.Redirect(p+path+"/index.html", 301)
+        ctx.Redirect(prefix+"index.html", 301)
     })
-
-    fn := httpSwagger.Handler(httpSwagger.URL(p + path + "/doc.json"))
     ver.Get(path+"/{any:path}", func(ctx iris.Context) {
-        fn(ctx.ResponseWriter(), ctx.Request())
+        name := ctx.Params().Get("any")
+        if k := strings.LastIndex(name, "."); k != -1 {
+            format := name[k+1:]
+            if doc, ok := docs[name[:k]]; ok && (format == "json" || format == "yaml") {
+                serveSwaggerDoc(ctx, doc, format)
+                return
+            }
+        }
+
+        switch name {
+        case "", "/":
+            ctx.Redirect(prefix+"index.html", 301)
+        case "index.html":
+            ctx.ContentType("text/html; charset=utf-8")
+            _ = index.Execute(ctx.ResponseWriter(), data)
+        default:
+            files.ServeHTTP(ctx.ResponseWriter(), ctx.Request())
+        }
     })
 }
+
+// swagger ui 页面数据
+type swaggerIndexData struct {
+    Title                string
+    Config               template.JS
+    OAuth                template.JS
+    PersistAuthorization bool
+}
+
+// 生成页面数据
+func makeSwaggerIndexData(conf SwaggerConfig) *swaggerIndexData {
+    data := &swaggerIndexData{
+        Title:                conf.Title,
+        PersistAuthorization: conf.PersistAuthorization,
+    }
+    if data.Title == "" {
+        data.Title = "Swagger UI"
+    }
+
+    ui := map[string]interface{}{
+        "deepLinking":              !conf.DisableDeepLinking,
+        "docExpansion":             "list",
+        "defaultModelsExpandDepth": 1,
+        "persistAuthorization":     conf.PersistAuthorization,
+    }
+    if conf.DocExpansion != "" {
+        ui["docExpansion"] = strings.ToLower(conf.DocExpansion)
+    }
+    if conf.DefaultModelsExpandDepth != nil {
+        ui["defaultModelsExpandDepth"] = *conf.DefaultModelsExpandDepth
+    }
+    if len(conf.Specs) > 0 {
+        ui["urls"] = conf.Specs
+        if conf.PrimarySpec != "" {
+            ui["urls.primaryName"] = conf.PrimarySpec
+        }
+    } else {
+        ui["url"] = conf.URL
+    }
+    data.Config = marshalSwaggerJS(ui)
+
+    if conf.OAuth != nil {
+        data.OAuth = marshalSwaggerJS(conf.OAuth)
+    }
+    return data
+}
+
+// 转为可以嵌入脚本的json
+func marshalSwaggerJS(v interface{}) template.JS {
+    bs, _ := json.Marshal(v)
+    return template.JS(bs)
+}
+
+const swaggerIndexTempl = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="UTF-8">
+  <title>{{.Title}}</title>
+  <link rel="stylesheet" type="text/css" href="./swagger-ui.css" >
+  <link rel="icon" type="image/png" href="./favicon-32x32.png" sizes="32x32" />
+  <link rel="icon" type="image/png" href="./favicon-16x16.png" sizes="16x16" />
+  <style>
+    html { box-sizing: border-box; overflow: -moz-scrollbars-vertical; overflow-y: scroll; }
+    *, *:before, *:after { box-sizing: inherit; }
+    body { margin: 0; background: #fafafa; }
+  </style>
+</head>
+
+<body>
+<div id="swagger-ui"></div>
+
+<script src="./swagger-ui-bundle.js"> </script>
+<script src="./swagger-ui-standalone-preset.js"> </script>
+<script>
+window.onload = function() {
+  var config = {{.Config}};
+  config.dom_id = "#swagger-ui";
+  config.validatorUrl = null;
+  config.presets = [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset];
+  config.plugins = [SwaggerUIBundle.plugins.DownloadUrl];
+  config.layout = "StandaloneLayout";
+  {{- if .PersistAuthorization}}
+  var authKey = "authorized:" + location.pathname;
+  config.onComplete = function() {
+    var saved = localStorage.getItem(authKey);
+    if (saved) {
+      ui.authActions.authorize(JSON.parse(saved));
+    }
+  };
+  {{- end}}
+
+  var ui = SwaggerUIBundle(config);
+  {{- if .PersistAuthorization}}
+  // 兼容不支持 persistAuthorization 的 swagger ui 版本
+  var authorize = ui.authActions.authorize, logout = ui.authActions.logout;
+  ui.authActions.authorize = function(payload) {
+    var saved = JSON.parse(localStorage.getItem(authKey) || "{}");
+    for (var k in payload) { saved[k] = payload[k]; }
+    localStorage.setItem(authKey, JSON.stringify(saved));
+    return authorize(payload);
+  };
+  ui.authActions.logout = function(names) {
+    var saved = JSON.parse(localStorage.getItem(authKey) || "{}");
+    (names || []).forEach(function(k) { delete saved[k]; });
+    localStorage.setItem(authKey, JSON.stringify(saved));
+    return logout(names);
+  };
+  {{- end}}
+  {{- if .OAuth}}
+  ui.initOAuth({{.OAuth}});
+  {{- end}}
+
+  window.ui = ui;
+};
+</script>
+</body>
+</html>
+`
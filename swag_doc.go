@@ -0,0 +1,138 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  swagger 文档
+-------------------------------------------------
+*/
+
+package ziris
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "io/ioutil"
+
+    "github.com/kataras/iris/v12"
+    "gopkg.in/yaml.v2"
+)
+
+// swagger 文档来源, 文档内容可以是json或yaml
+type SwaggerDoc func() ([]byte, error)
+
+// 使用文档内容作为来源, 如 go:embed 或 go-bindata 嵌入的文档
+func SwaggerDocBytes(doc []byte) SwaggerDoc {
+    return func() ([]byte, error) {
+        return doc, nil
+    }
+}
+
+// 使用文件作为来源, 每次请求时读取文件, 修改文件后刷新页面即可生效
+func SwaggerDocFile(name string) SwaggerDoc {
+    return func() ([]byte, error) {
+        return ioutil.ReadFile(name)
+    }
+}
+
+// 使用函数作为来源, 每次请求时调用函数生成文档
+func SwaggerDocFunc(fn func() ([]byte, error)) SwaggerDoc {
+    return fn
+}
+
+// 检查文档是否为json
+func isJsonDoc(doc []byte) bool {
+    doc = bytes.TrimSpace(doc)
+    return len(doc) > 0 && doc[0] == '{'
+}
+
+// 读取文档并转为json
+func (fn SwaggerDoc) JSON() ([]byte, error) {
+    doc, err := fn()
+    if err != nil || isJsonDoc(doc) {
+        return doc, err
+    }
+
+    var v yaml.MapSlice
+    if err = yaml.Unmarshal(doc, &v); err != nil {
+        return nil, err
+    }
+    var buff bytes.Buffer
+    if err = writeOrderedJson(&buff, v); err != nil {
+        return nil, err
+    }
+    return buff.Bytes(), nil
+}
+
+// 读取文档并转为yaml
+func (fn SwaggerDoc) YAML() ([]byte, error) {
+    doc, err := fn()
+    if err != nil || !isJsonDoc(doc) {
+        return doc, err
+    }
+
+    // json是yaml的子集, 解析为 yaml.MapSlice 可以保持字段顺序
+    var v yaml.MapSlice
+    if err = yaml.Unmarshal(doc, &v); err != nil {
+        return nil, err
+    }
+    return yaml.Marshal(v)
+}
+
+// 保持字段顺序写入json
+func writeOrderedJson(buff *bytes.Buffer, v interface{}) error {
+    switch v := v.(type) {
+    case yaml.MapSlice:
+        buff.WriteByte('{')
+        for i, item := range v {
+            if i > 0 {
+                buff.WriteByte(',')
+            }
+            key, _ := json.Marshal(fmt.Sprint(item.Key))
+            buff.Write(key)
+            buff.WriteByte(':')
+            if err := writeOrderedJson(buff, item.Value); err != nil {
+                return err
+            }
+        }
+        buff.WriteByte('}')
+    case []interface{}:
+        buff.WriteByte('[')
+        for i, item := range v {
+            if i > 0 {
+                buff.WriteByte(',')
+            }
+            if err := writeOrderedJson(buff, item); err != nil {
+                return err
+            }
+        }
+        buff.WriteByte(']')
+    default:
+        bs, err := json.Marshal(v)
+        if err != nil {
+            return err
+        }
+        buff.Write(bs)
+    }
+    return nil
+}
+
+// 输出文档, format为json或yaml
+func serveSwaggerDoc(ctx iris.Context, doc SwaggerDoc, format string) {
+    var bs []byte
+    var err error
+    contentType := "application/json; charset=utf-8"
+    if format == "yaml" {
+        bs, err = doc.YAML()
+        contentType = "application/x-yaml; charset=utf-8"
+    } else {
+        bs, err = doc.JSON()
+    }
+    if err != nil {
+        ctx.StatusCode(500)
+        _, _ = ctx.WriteString(err.Error())
+        return
+    }
+    ctx.ContentType(contentType)
+    _, _ = ctx.Write(bs)
+}
@@ -0,0 +1,125 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package ziris
+
+import (
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/kataras/iris/v12"
+)
+
+func TestSetupSwaggerWithConfig(t *testing.T) {
+    depth := -1
+    app := iris.New()
+    SetupSwaggerWithConfig(app.Party("/api"), "/swagger", SwaggerConfig{
+        Title:                    "</title>test",
+        DefaultModelsExpandDepth: &depth,
+        PersistAuthorization:     true,
+        OAuth:                    &SwaggerOAuthConfig{ClientID: "cid"},
+        Specs:                    []SwaggerSpec{{Name: "v1", URL: "/v1/doc.json"}, {Name: "v2", URL: "/v2/doc.json"}},
+        PrimarySpec:              "v2",
+    })
+    if err := app.Build(); err != nil {
+        t.Fatal(err)
+    }
+
+    w := httptest.NewRecorder()
+    app.ServeHTTP(w, httptest.NewRequest("GET", "/api/swagger", nil))
+    if w.Code != 301 || w.Header().Get("Location") != "/api/swagger/index.html" {
+        t.Fatal("重定向错误", w.Code, w.Header())
+    }
+
+    w = httptest.NewRecorder()
+    app.ServeHTTP(w, httptest.NewRequest("GET", "/api/swagger/index.html", nil))
+    body := w.Body.String()
+    for _, s := range []string{
+        "<title>&lt;/title&gt;test</title>",
+        `"defaultModelsExpandDepth":-1`,
+        `"urls":[{"name":"v1","url":"/v1/doc.json"},{"name":"v2","url":"/v2/doc.json"}]`,
+        `"urls.primaryName":"v2"`,
+        `ui.initOAuth({"clientId":"cid"})`,
+        "localStorage",
+    } {
+        if !strings.Contains(body, s) {
+            t.Fatal("页面中没有找到", s, body)
+        }
+    }
+
+    w = httptest.NewRecorder()
+    app.ServeHTTP(w, httptest.NewRequest("GET", "/api/swagger/swagger-ui.css", nil))
+    if w.Code != 200 || w.Body.Len() == 0 {
+        t.Fatal("静态文件错误", w.Code)
+    }
+}
+
+func TestSwaggerDoc(t *testing.T) {
+    yamlDoc := "swagger: \"2.0\"\ninfo:\n  title: test\n  version: \"1\"\npaths:\n  /a:\n    get:\n      responses:\n        200:\n          description: ok\n"
+    jsonDoc := `{"swagger":"2.0","info":{"title":"test","version":"1"},"paths":{}}`
+
+    app := iris.New()
+    SetupSwaggerWithConfig(app, "/swagger", SwaggerConfig{
+        Specs: []SwaggerSpec{
+            {Name: "v1", Doc: SwaggerDocBytes([]byte(yamlDoc))},
+            {Name: "v2", Doc: SwaggerDocFunc(func() ([]byte, error) { return []byte(jsonDoc), nil })},
+        },
+    })
+    if err := app.Build(); err != nil {
+        t.Fatal(err)
+    }
+    get := func(target string) string {
+        w := httptest.NewRecorder()
+        app.ServeHTTP(w, httptest.NewRequest("GET", target, nil))
+        if w.Code != 200 {
+            t.Fatal("状态码错误", target, w.Code)
+        }
+        return w.Body.String()
+    }
+
+    if s := get("/swagger/v1.json"); s != `{"swagger":"2.0","info":{"title":"test","version":"1"},"paths":{"/a":{"get":{"responses":{"200":{"description":"ok"}}}}}}` {
+        t.Fatal("yaml转json错误", s)
+    }
+    if s := get("/swagger/v1.yaml"); s != yamlDoc {
+        t.Fatal("yaml输出错误", s)
+    }
+    if s := get("/swagger/v2.json"); s != jsonDoc {
+        t.Fatal("json输出错误", s)
+    }
+    if s := get("/swagger/v2.yaml"); !strings.HasPrefix(s, "swagger: \"2.0\"\ninfo:\n  title: test\n") {
+        t.Fatal("json转yaml错误", s)
+    }
+    if s := get("/swagger/index.html"); !strings.Contains(s, `"urls":[{"name":"v1","url":"/swagger/v1.json"},{"name":"v2","url":"/swagger/v2.json"}]`) {
+        t.Fatal("页面错误", s)
+    }
+}
+
+func TestSwaggerSpecs(t *testing.T) {
+    app := iris.New()
+    SetupSwaggerWithConfig(app, "/swagger", SwaggerConfig{
+        Specs: []SwaggerSpec{
+            {Name: "doc", Doc: SwaggerDocBytes([]byte(`{"swagger":"2.0","info":{"title":"spec"}}`))},
+        },
+    })
+    if err := app.Build(); err != nil {
+        t.Fatal(err)
+    }
+    w := httptest.NewRecorder()
+    app.ServeHTTP(w, httptest.NewRequest("GET", "/swagger/doc.json", nil))
+    if w.Code != 200 || !strings.Contains(w.Body.String(), `"title":"spec"`) {
+        t.Fatal("设置Specs后不应该提供默认文档", w.Code, w.Body.String())
+    }
+
+    defer func() {
+        if recover() == nil {
+            t.Fatal("没有设置URL或Doc的文档应该panic")
+        }
+    }()
+    SetupSwaggerWithConfig(iris.New(), "/swagger", SwaggerConfig{Specs: []SwaggerSpec{{Name: "v1"}}})
+}
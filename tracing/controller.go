@@ -0,0 +1,64 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  控制器链路追踪
+-------------------------------------------------
+*/
+
+package tracing
+
+import (
+    "fmt"
+
+    "github.com/kataras/iris/v12"
+
+    "github.com/zlyuancn/ziris/auto_route"
+    "github.com/zlyuancn/ziris/ctx_info"
+)
+
+// 链路id样式标记
+const TraceIDFlag ctx_info.FormatFlag = "trace_id"
+
+func init() {
+    ctx_info.RegisterFormatFlag(string(TraceIDFlag), func(ctx iris.Context) string {
+        if sc, ok := SpanContextFromContext(ctx.Request().Context()); ok && sc.IsValid() {
+            return sc.TraceID.String()
+        }
+        return ""
+    })
+}
+
+// 返回追踪控制器方法的请求中间件
+// 每次调用控制器方法都会创建一个名为 controller.method 的跨度, 如果请求头中有 traceparent 会以它为父跨度
+// 跨度会被放入 ctx.Request().Context() 中, 控制器方法中可以使用 SpanFromContext 获取
+func (m *Tracer) ReqMiddleware() auto_route.ReqMiddleware {
+    return func(ctx iris.Context, arg *auto_route.ReqArg) {
+        c := ctx.Request().Context()
+        if sc, ok := ParseTraceparent(ctx.GetHeader(TraceparentHeader)); ok {
+            c = ContextWithRemoteSpanContext(c, sc)
+        }
+
+        name := arg.ControllerName()
+        if arg.ControlMethod() != "" {
+            name += "." + arg.ControlMethod()
+        }
+        c, span := m.Start(c, name)
+        span.SetAttribute("http.method", ctx.Method())
+        span.SetAttribute("http.route", arg.Route())
+        span.SetAttribute("http.params", arg.Params())
+        ctx.ResetRequest(ctx.Request().WithContext(c))
+
+        arg.OnFinish(func(ctx iris.Context, arg *auto_route.ReqArg) {
+            status := ctx.GetStatusCode()
+            if arg.IsPanic() {
+                status = 500
+                span.SetError("panic")
+            } else if status >= 500 {
+                span.SetError(fmt.Sprintf("http status %d", status))
+            }
+            span.SetAttribute("http.status_code", status)
+            span.End()
+        })
+    }
+}
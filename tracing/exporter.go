@@ -0,0 +1,90 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  跨度导出器
+-------------------------------------------------
+*/
+
+package tracing
+
+import (
+    "encoding/json"
+    "io"
+    "sync"
+    "time"
+)
+
+// 跨度导出器
+type Exporter interface {
+    // 导出一个已结束的跨度
+    Export(span *Span)
+}
+
+// 内存导出器, 一般用于测试
+type MemoryExporter struct {
+    mx    sync.Mutex
+    spans []*Span
+}
+
+// 创建内存导出器
+func NewMemoryExporter() *MemoryExporter {
+    return new(MemoryExporter)
+}
+
+func (m *MemoryExporter) Export(span *Span) {
+    m.mx.Lock()
+    m.spans = append(m.spans, span)
+    m.mx.Unlock()
+}
+
+// 返回已导出的跨度
+func (m *MemoryExporter) Spans() []*Span {
+    m.mx.Lock()
+    defer m.mx.Unlock()
+    return append(([]*Span)(nil), m.spans...)
+}
+
+// 清空已导出的跨度
+func (m *MemoryExporter) Reset() {
+    m.mx.Lock()
+    m.spans = nil
+    m.mx.Unlock()
+}
+
+type writerExporter struct {
+    mx sync.Mutex
+    w  io.Writer
+}
+
+// 创建将跨度以json行的形式写入w的导出器, 如 NewWriterExporter(os.Stdout)
+func NewWriterExporter(w io.Writer) Exporter {
+    return &writerExporter{w: w}
+}
+
+func (m *writerExporter) Export(span *Span) {
+    span.mx.Lock()
+    data := map[string]interface{}{
+        "name":       span.Name,
+        "trace_id":   span.SpanContext.TraceID.String(),
+        "span_id":    span.SpanContext.SpanID.String(),
+        "start_time": span.StartTime.Format(time.RFC3339Nano),
+        "duration":   span.EndTime.Sub(span.StartTime).String(),
+        "attributes": span.Attributes,
+    }
+    if span.ParentSpanID.IsValid() {
+        data["parent_span_id"] = span.ParentSpanID.String()
+    }
+    if span.Error != "" {
+        data["error"] = span.Error
+    }
+    bs, err := json.Marshal(data)
+    span.mx.Unlock()
+    if err != nil {
+        return
+    }
+
+    m.mx.Lock()
+    _, _ = m.w.Write(append(bs, '\n'))
+    m.mx.Unlock()
+}
@@ -0,0 +1,204 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :  链路追踪
+-------------------------------------------------
+*/
+
+package tracing
+
+import (
+    "context"
+    "crypto/rand"
+    "encoding/hex"
+    "sync"
+    "time"
+)
+
+// W3C链路追踪请求头
+const TraceparentHeader = "traceparent"
+
+// 链路id
+type TraceID [16]byte
+
+func (m TraceID) IsValid() bool {
+    return m != TraceID{}
+}
+
+func (m TraceID) String() string {
+    return hex.EncodeToString(m[:])
+}
+
+// 跨度id
+type SpanID [8]byte
+
+func (m SpanID) IsValid() bool {
+    return m != SpanID{}
+}
+
+func (m SpanID) String() string {
+    return hex.EncodeToString(m[:])
+}
+
+// 跨度上下文
+type SpanContext struct {
+    TraceID TraceID
+    SpanID  SpanID
+    // 追踪标记, 最低位表示是否采样
+    Flags byte
+    // 是否来自远程(请求头)
+    Remote bool
+}
+
+func (m SpanContext) IsValid() bool {
+    return m.TraceID.IsValid() && m.SpanID.IsValid()
+}
+
+// 返回W3C traceparent格式的字符串, 如 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+func (m SpanContext) Traceparent() string {
+    return "00-" + m.TraceID.String() + "-" + m.SpanID.String() + "-" + hex.EncodeToString([]byte{m.Flags})
+}
+
+// 解析W3C traceparent格式的字符串
+func ParseTraceparent(s string) (SpanContext, bool) {
+    var sc SpanContext
+    // version-traceid-spanid-flags
+    if len(s) < 55 || s[2] != '-' || s[35] != '-' || s[52] != '-' {
+        return sc, false
+    }
+    version, err := hex.DecodeString(s[:2])
+    if err != nil || version[0] == 0xff || (version[0] == 0 && len(s) != 55) {
+        return sc, false
+    }
+    if _, err = hex.Decode(sc.TraceID[:], []byte(s[3:35])); err != nil {
+        return sc, false
+    }
+    if _, err = hex.Decode(sc.SpanID[:], []byte(s[36:52])); err != nil {
+        return sc, false
+    }
+    flags, err := hex.DecodeString(s[53:55])
+    if err != nil {
+        return sc, false
+    }
+    sc.Flags = flags[0]
+    sc.Remote = true
+    return sc, sc.IsValid()
+}
+
+// 跨度
+type Span struct {
+    // 名称
+    Name string
+    // 跨度上下文
+    SpanContext SpanContext
+    // 父跨度id, 根跨度为空
+    ParentSpanID SpanID
+    // 开始时间
+    StartTime time.Time
+    // 结束时间
+    EndTime time.Time
+    // 属性
+    Attributes map[string]interface{}
+    // 错误信息
+    Error string
+
+    mx     sync.Mutex
+    ended  bool
+    tracer *Tracer
+}
+
+// 设置属性
+func (m *Span) SetAttribute(key string, value interface{}) {
+    m.mx.Lock()
+    m.Attributes[key] = value
+    m.mx.Unlock()
+}
+
+// 设置错误信息
+func (m *Span) SetError(err string) {
+    m.mx.Lock()
+    m.Error = err
+    m.mx.Unlock()
+}
+
+// 结束跨度并导出, 多次调用只有第一次有效
+func (m *Span) End() {
+    m.mx.Lock()
+    if m.ended {
+        m.mx.Unlock()
+        return
+    }
+    m.ended = true
+    m.EndTime = time.Now()
+    m.mx.Unlock()
+
+    if m.tracer != nil && m.tracer.exporter != nil {
+        m.tracer.exporter.Export(m)
+    }
+}
+
+// 返回跨度持续时间
+func (m *Span) Duration() time.Duration {
+    return m.EndTime.Sub(m.StartTime)
+}
+
+type spanKey struct{}
+type remoteSpanContextKey struct{}
+
+// 将跨度放入上下文
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+    return context.WithValue(ctx, spanKey{}, span)
+}
+
+// 从上下文中获取跨度, 如果不存在返回nil
+func SpanFromContext(ctx context.Context) *Span {
+    span, _ := ctx.Value(spanKey{}).(*Span)
+    return span
+}
+
+// 将远程跨度上下文放入上下文, 之后在这个上下文中开始的跨度会以它为父跨度
+func ContextWithRemoteSpanContext(ctx context.Context, sc SpanContext) context.Context {
+    return context.WithValue(ctx, remoteSpanContextKey{}, sc)
+}
+
+// 从上下文中获取跨度上下文, 优先返回跨度的上下文, 其次返回远程跨度上下文
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+    if span := SpanFromContext(ctx); span != nil {
+        return span.SpanContext, true
+    }
+    sc, ok := ctx.Value(remoteSpanContextKey{}).(SpanContext)
+    return sc, ok
+}
+
+// 追踪器
+type Tracer struct {
+    exporter Exporter
+}
+
+// 创建追踪器, exporter为nil时不会导出跨度
+func NewTracer(exporter Exporter) *Tracer {
+    return &Tracer{exporter: exporter}
+}
+
+// 开始一个跨度, 返回的上下文中包含这个跨度, 使用完毕后必须调用 span.End()
+func (m *Tracer) Start(ctx context.Context, name string) (context.Context, *Span) {
+    span := &Span{
+        Name:       name,
+        StartTime:  time.Now(),
+        Attributes: make(map[string]interface{}),
+        tracer:     m,
+    }
+
+    if parent, ok := SpanContextFromContext(ctx); ok && parent.IsValid() {
+        span.SpanContext.TraceID = parent.TraceID
+        span.SpanContext.Flags = parent.Flags
+        span.ParentSpanID = parent.SpanID
+    } else {
+        _, _ = rand.Read(span.SpanContext.TraceID[:])
+        span.SpanContext.Flags = 1
+    }
+    _, _ = rand.Read(span.SpanContext.SpanID[:])
+
+    return ContextWithSpan(ctx, span), span
+}
@@ -0,0 +1,72 @@
+/*
+-------------------------------------------------
+   Author :       Zhang Fan
+   date：         2026/10/19
+   Description :
+-------------------------------------------------
+*/
+
+package tracing
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/kataras/iris/v12"
+
+    "github.com/zlyuancn/ziris/auto_route"
+)
+
+type TestTraceController struct{}
+
+var testTraceParent SpanContext
+
+func (t *TestTraceController) GetUser(ctx iris.Context) {
+    span := SpanFromContext(ctx.Request().Context())
+    if span != nil {
+        testTraceParent = span.SpanContext
+    }
+    _, _ = ctx.WriteString("ok")
+}
+
+func TestParseTraceparent(t *testing.T) {
+    s := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+    sc, ok := ParseTraceparent(s)
+    if !ok || sc.Traceparent() != s {
+        t.Fatal("解析失败", sc.Traceparent())
+    }
+    for _, s := range []string{"", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", "zz-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"} {
+        if _, ok := ParseTraceparent(s); ok {
+            t.Fatal("不应该解析成功", s)
+        }
+    }
+}
+
+func TestTracerReqMiddleware(t *testing.T) {
+    exporter := NewMemoryExporter()
+    app := iris.New()
+    auto_route.RegistryController(app, (*TestTraceController)(nil), NewTracer(exporter).ReqMiddleware())
+    if err := app.Build(); err != nil {
+        t.Fatal(err)
+    }
+
+    req := httptest.NewRequest(http.MethodGet, "/test_trace/user/1", nil)
+    req.Header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+    app.ServeHTTP(httptest.NewRecorder(), req)
+
+    spans := exporter.Spans()
+    if len(spans) != 1 {
+        t.Fatal("跨度数量错误", len(spans))
+    }
+    span := spans[0]
+    if span.Name != "test_trace.user" || span.SpanContext.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" || span.ParentSpanID.String() != "00f067aa0ba902b7" {
+        t.Fatal("跨度错误", span.Name, span.SpanContext.Traceparent(), span.ParentSpanID)
+    }
+    if span.Attributes["http.route"] != "/test_trace/user" || span.Attributes["http.params"] != "1" || span.Attributes["http.status_code"] != 200 {
+        t.Fatal("跨度属性错误", span.Attributes)
+    }
+    if testTraceParent != span.SpanContext {
+        t.Fatal("控制器方法中获取的跨度错误")
+    }
+}